@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	// mariadb
+	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/rafaelsq/boiler/pkg/migration"
+	"github.com/rafaelsq/boiler/pkg/storage/sqlite"
+)
+
+const usage = `usage: migrate [flags] command
+
+commands:
+  up        apply all pending migrations
+  down N    revert the last N migrations
+  status    list migrations and whether they are applied
+  force V   mark the schema as clean at version V without running migrations
+
+flags:
+`
+
+func open(driver, dsn string) (*sql.DB, error) {
+	switch driver {
+	case migration.MySQL:
+		if len(dsn) == 0 {
+			dsn = "root:boiler@tcp(127.0.0.1:3307)/boiler?timeout=5s&parseTime=true&loc=UTC"
+		}
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, err
+		}
+		return db, db.Ping()
+	case migration.SQLite:
+		if len(dsn) == 0 {
+			dsn = "boiler.db"
+		}
+		return sqlite.Open(dsn)
+	}
+
+	return nil, fmt.Errorf("unknown storage \"%s\"", driver)
+}
+
+func number(args []string) uint {
+	if len(args) != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	n, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil {
+		log.Fatalf("invalid number \"%s\"", args[1])
+	}
+
+	return uint(n)
+}
+
+func main() {
+	var driver = flag.String("storage", "mysql", "storage backend; mysql or sqlite")
+	var dsn = flag.String("dsn", "", "storage data source name; defaults to the local MariaDB or boiler.db")
+
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := open(*driver, *dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := migration.New(db, *driver)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		err = m.Up(ctx)
+	case "down":
+		err = m.Down(ctx, int(number(args)))
+	case "force":
+		err = m.Force(ctx, number(args))
+	case "status":
+		var states []*migration.State
+		states, err = m.Status(ctx)
+		for _, s := range states {
+			mark := " "
+			if s.Dirty {
+				mark = "!"
+			} else if s.Applied {
+				mark = "x"
+			}
+			fmt.Printf("[%s] %04d %s\n", mark, s.Version, s.Name)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/log"
+	"github.com/rafaelsq/errors"
 )
 
 func AddUserHandle(service iface.Service) http.HandlerFunc {
@@ -58,7 +59,21 @@ func ListUsersHandle(service iface.Service) http.HandlerFunc {
 			}
 		}
 
-		users, err := service.FilterUsers(r.Context(), iface.FilterUsers{Limit: uint(limit)})
+		includeDeleted, ok := IncludeDeleted(w, r)
+		if !ok {
+			return
+		}
+
+		cursor, ok := Cursor(w, r)
+		if !ok {
+			return
+		}
+
+		users, next, err := service.FilterUsers(r.Context(), iface.FilterUsers{
+			Limit:          uint(limit),
+			Cursor:         cursor,
+			IncludeDeleted: includeDeleted,
+		})
 		if err != nil {
 			log.Log(err)
 			w.WriteHeader(http.StatusInternalServerError)
@@ -66,8 +81,66 @@ func ListUsersHandle(service iface.Service) http.HandlerFunc {
 			return
 		}
 
+		Page(w, r, "users", users, next)
+	}
+}
+
+func UpdateUserHandle(service iface.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+		if err != nil || userID == 0 {
+			Fail(w, r, http.StatusBadRequest, "invalid user ID")
+			return
+		}
+
+		version, ok := IfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		payload := struct {
+			Name string `json:"name"`
+		}{}
+
+		err = json.NewDecoder(r.Body).Decode(&payload)
+		if err != nil {
+			Fail(w, r, http.StatusBadRequest, "could not parse payload")
+			return
+		}
+
+		payload.Name = strings.TrimSpace(payload.Name)
+		if len(payload.Name) == 0 {
+			Fail(w, r, http.StatusBadRequest, "empty name")
+			return
+		}
+
+		err = service.UpdateUser(r.Context(), userID, version, payload.Name)
+		if err != nil {
+			switch errors.Cause(err) {
+			case iface.ErrNotFound:
+				Fail(w, r, http.StatusNotFound, "user not found")
+				return
+			case iface.ErrConflict:
+				Fail(w, r, http.StatusConflict, "version conflict")
+				return
+			}
+
+			log.Log(err)
+			Fail(w, r, http.StatusInternalServerError, "service failed")
+			return
+		}
+
+		// the new ETag conditions the client's next write
+		user, err := service.GetUserByID(r.Context(), userID)
+		if err != nil {
+			log.Log(err)
+			Fail(w, r, http.StatusInternalServerError, "service failed")
+			return
+		}
+
+		ETag(w, user.Version)
 		JSON(w, r, map[string]interface{}{
-			"users": users,
+			"user": user,
 		})
 	}
 }
@@ -80,13 +153,63 @@ func DeleteUserHandle(service iface.Service) http.HandlerFunc {
 			return
 		}
 
-		err = service.DeleteUser(r.Context(), userID)
+		version, ok := IfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		err = service.DeleteUser(r.Context(), userID, version)
+		if err != nil {
+			switch errors.Cause(err) {
+			case iface.ErrNotFound:
+				Fail(w, r, http.StatusNotFound, "user not found")
+				return
+			case iface.ErrConflict:
+				Fail(w, r, http.StatusConflict, "version conflict")
+				return
+			}
+
+			log.Log(err)
+			Fail(w, r, http.StatusInternalServerError, "service failed")
+			return
+		}
+
+		// the delete bumped the version; the new ETag conditions the restore
+		ETag(w, version+1)
+		JSON(w, r, nil)
+	}
+}
+
+func RestoreUserHandle(service iface.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+		if err != nil || userID == 0 {
+			Fail(w, r, http.StatusBadRequest, "invalid user ID")
+			return
+		}
+
+		version, ok := IfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		err = service.RestoreUser(r.Context(), userID, version)
 		if err != nil {
+			switch errors.Cause(err) {
+			case iface.ErrNotFound:
+				Fail(w, r, http.StatusNotFound, "deleted user not found")
+				return
+			case iface.ErrConflict:
+				Fail(w, r, http.StatusConflict, "version conflict")
+				return
+			}
+
 			log.Log(err)
 			Fail(w, r, http.StatusInternalServerError, "service failed")
 			return
 		}
 
+		ETag(w, version+1)
 		JSON(w, r, nil)
 	}
 }
@@ -106,6 +229,7 @@ func GetUserHandle(service iface.Service) http.HandlerFunc {
 			return
 		}
 
+		ETag(w, user.Version)
 		JSON(w, r, map[string]interface{}{
 			"user": user,
 		})
@@ -183,15 +307,37 @@ func ListEmailsHandle(service iface.Service) http.HandlerFunc {
 			return
 		}
 
-		emails, err := service.FilterEmails(r.Context(), iface.FilterEmails{UserID: userID})
+		includeDeleted, ok := IncludeDeleted(w, r)
+		if !ok {
+			return
+		}
+
+		var limit uint64
+		if raw := r.URL.Query().Get("limit"); len(raw) != 0 {
+			limit, err = strconv.ParseUint(raw, 10, 32)
+			if err != nil || limit == 0 {
+				Fail(w, r, http.StatusBadRequest, "invalid URL query limit")
+				return
+			}
+		}
+
+		cursor, ok := Cursor(w, r)
+		if !ok {
+			return
+		}
+
+		emails, next, err := service.FilterEmails(r.Context(), iface.FilterEmails{
+			UserID:         userID,
+			Limit:          uint(limit),
+			Cursor:         cursor,
+			IncludeDeleted: includeDeleted,
+		})
 		if err != nil {
 			log.Log(err)
 			Fail(w, r, http.StatusInternalServerError, "service failed")
 			return
 		}
 
-		JSON(w, r, map[string]interface{}{
-			"emails": emails,
-		})
+		Page(w, r, "emails", emails, next)
 	}
 }
@@ -16,6 +16,8 @@ import (
 	"github.com/rafaelsq/boiler/pkg/entity"
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/mock"
+	"github.com/rafaelsq/boiler/pkg/service"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -125,6 +127,132 @@ func TestAddUserHandle(t *testing.T) {
 	}
 }
 
+func TestUpdateUserHandle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// etag is the ETag of the last response
+	var etag string
+	do := func(m iface.Service, path, ifMatch, body string) (int, string) {
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Patch("/users/{userID:[0-9]+}", rest.UpdateUserHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		req, err := http.NewRequest(http.MethodPatch, ts.URL+path, bytes.NewBufferString(body))
+		assert.Nil(t, err)
+		if len(ifMatch) != 0 {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+
+		b, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err)
+		res.Body.Close()
+
+		etag = res.Header.Get("ETag")
+		return res.StatusCode, string(b)
+	}
+
+	// succeed with the updated user and its ETag
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().UpdateUser(gomock.Any(), int64(4), int64(2), "Jane").Return(nil)
+		m.EXPECT().GetUserByID(gomock.Any(), int64(4)).Return(&entity.User{ID: 4, Name: "Jane", Version: 3}, nil)
+
+		status, body := do(m, "/users/4?debug", `"2"`, `{"name":" Jane "}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `"3"`, etag)
+
+		var payload struct {
+			User entity.User `json:"user"`
+		}
+		assert.Nil(t, json.Unmarshal([]byte(body), &payload))
+		assert.Equal(t, "Jane", payload.User.Name)
+		assert.Equal(t, int64(3), payload.User.Version)
+	}
+
+	// fails if the updated user cannot be read
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().UpdateUser(gomock.Any(), int64(4), int64(2), "Jane").Return(nil)
+		m.EXPECT().GetUserByID(gomock.Any(), int64(4)).Return(nil, fmt.Errorf("opz"))
+
+		status, body := do(m, "/users/4?debug", `"2"`, `{"name":"Jane"}`)
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Equal(t, "service failed", body)
+	}
+
+	// fails if invalid userID
+	{
+		status, body := do(mock.NewMockService(ctrl), "/users/0?debug", `"2"`, `{"name":"Jane"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid user ID", body)
+	}
+
+	// fails if If-Match is missing
+	{
+		status, body := do(mock.NewMockService(ctrl), "/users/4?debug", "", `{"name":"Jane"}`)
+		assert.Equal(t, http.StatusPreconditionRequired, status)
+		assert.Equal(t, "missing If-Match", body)
+	}
+
+	// fails if If-Match is invalid
+	{
+		status, body := do(mock.NewMockService(ctrl), "/users/4?debug", `W/"2"`, `{"name":"Jane"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid If-Match", body)
+	}
+
+	// fails if invalid payload
+	{
+		status, body := do(mock.NewMockService(ctrl), "/users/4?debug", `"2"`, `{`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "could not parse payload", body)
+	}
+
+	// fails if empty name
+	{
+		status, body := do(mock.NewMockService(ctrl), "/users/4?debug", `"2"`, `{"name":" "}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "empty name", body)
+	}
+
+	// fails if user not found
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().UpdateUser(gomock.Any(), int64(4), int64(2), "Jane").Return(iface.ErrNotFound)
+
+		status, body := do(m, "/users/4?debug", `"2"`, `{"name":"Jane"}`)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, "user not found", body)
+	}
+
+	// fails if version is stale
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().UpdateUser(gomock.Any(), int64(4), int64(2), "Jane").Return(iface.ErrConflict)
+
+		status, body := do(m, "/users/4?debug", `"2"`, `{"name":"Jane"}`)
+		assert.Equal(t, http.StatusConflict, status)
+		assert.Equal(t, "version conflict", body)
+	}
+
+	// fails if service fails
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().UpdateUser(gomock.Any(), int64(4), int64(2), "Jane").Return(fmt.Errorf("opz"))
+
+		status, body := do(m, "/users/4?debug", `"2"`, `{"name":"Jane"}`)
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Equal(t, "service failed", body)
+	}
+}
+
 func TestDeleteUserHandle(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -134,7 +262,7 @@ func TestDeleteUserHandle(t *testing.T) {
 		m := mock.NewMockService(ctrl)
 
 		user := &entity.User{ID: 4, Name: "John"}
-		m.EXPECT().DeleteUser(gomock.Any(), user.ID).Return(nil)
+		m.EXPECT().DeleteUser(gomock.Any(), user.ID, int64(1)).Return(nil)
 
 		r := chi.NewRouter()
 		router.ApplyMiddlewares(r)
@@ -145,6 +273,7 @@ func TestDeleteUserHandle(t *testing.T) {
 
 		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/users/%d?debug", ts.URL, user.ID), nil)
 		assert.Nil(t, err)
+		req.Header.Set("If-Match", `"1"`)
 
 		res, err := http.DefaultClient.Do(req)
 		assert.Nil(t, err)
@@ -176,12 +305,95 @@ func TestDeleteUserHandle(t *testing.T) {
 		assert.Equal(t, "invalid user ID", string(b))
 	}
 
+	// fails if If-Match is missing
+	{
+		m := mock.NewMockService(ctrl)
+
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Delete("/users/{userID:[0-9]+}", rest.DeleteUserHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/users/4?debug", ts.URL), nil)
+		assert.Nil(t, err)
+
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusPreconditionRequired)
+
+		b, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err)
+		res.Body.Close()
+
+		assert.Equal(t, "missing If-Match", string(b))
+	}
+
+	// fails if user not found
+	{
+		m := mock.NewMockService(ctrl)
+
+		user := &entity.User{ID: 4, Name: "John"}
+		m.EXPECT().DeleteUser(gomock.Any(), user.ID, int64(1)).Return(iface.ErrNotFound)
+
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Delete("/users/{userID:[0-9]+}", rest.DeleteUserHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/users/%d?debug", ts.URL, user.ID), nil)
+		assert.Nil(t, err)
+		req.Header.Set("If-Match", `"1"`)
+
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusNotFound)
+
+		b, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err)
+		res.Body.Close()
+
+		assert.Equal(t, "user not found", string(b))
+	}
+
+	// fails if version is stale
+	{
+		m := mock.NewMockService(ctrl)
+
+		user := &entity.User{ID: 4, Name: "John"}
+		m.EXPECT().DeleteUser(gomock.Any(), user.ID, int64(1)).Return(iface.ErrConflict)
+
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Delete("/users/{userID:[0-9]+}", rest.DeleteUserHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/users/%d?debug", ts.URL, user.ID), nil)
+		assert.Nil(t, err)
+		req.Header.Set("If-Match", `"1"`)
+
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusConflict)
+
+		b, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err)
+		res.Body.Close()
+
+		assert.Equal(t, "version conflict", string(b))
+	}
+
 	// fails if service fails
 	{
 		m := mock.NewMockService(ctrl)
 
 		user := &entity.User{ID: 4, Name: "John"}
-		m.EXPECT().DeleteUser(gomock.Any(), user.ID).Return(fmt.Errorf("opz"))
+		m.EXPECT().DeleteUser(gomock.Any(), user.ID, int64(1)).Return(fmt.Errorf("opz"))
 
 		r := chi.NewRouter()
 		router.ApplyMiddlewares(r)
@@ -192,6 +404,7 @@ func TestDeleteUserHandle(t *testing.T) {
 
 		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/users/%d?debug", ts.URL, user.ID), nil)
 		assert.Nil(t, err)
+		req.Header.Set("If-Match", `"1"`)
 
 		res, err := http.DefaultClient.Do(req)
 		assert.Nil(t, err)
@@ -205,6 +418,88 @@ func TestDeleteUserHandle(t *testing.T) {
 	}
 }
 
+func TestRestoreUserHandle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	do := func(m iface.Service, path, ifMatch string) (int, string) {
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Post("/users/{userID:[0-9]+}/restore", rest.RestoreUserHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		req, err := http.NewRequest(http.MethodPost, ts.URL+path, nil)
+		assert.Nil(t, err)
+		if len(ifMatch) != 0 {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+
+		b, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err)
+		res.Body.Close()
+
+		return res.StatusCode, string(b)
+	}
+
+	// succeed
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().RestoreUser(gomock.Any(), int64(4), int64(3)).Return(nil)
+
+		status, _ := do(m, "/users/4/restore?debug", `"3"`)
+		assert.Equal(t, http.StatusOK, status)
+	}
+
+	// fails if invalid userID
+	{
+		status, body := do(mock.NewMockService(ctrl), "/users/0/restore?debug", `"3"`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid user ID", body)
+	}
+
+	// fails without If-Match
+	{
+		status, body := do(mock.NewMockService(ctrl), "/users/4/restore?debug", "")
+		assert.Equal(t, http.StatusPreconditionRequired, status)
+		assert.Equal(t, "missing If-Match", body)
+	}
+
+	// fails if version is stale
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().RestoreUser(gomock.Any(), int64(4), int64(3)).Return(iface.ErrConflict)
+
+		status, body := do(m, "/users/4/restore?debug", `"3"`)
+		assert.Equal(t, http.StatusConflict, status)
+		assert.Equal(t, "version conflict", body)
+	}
+
+	// fails if no deleted user
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().RestoreUser(gomock.Any(), int64(4), int64(3)).Return(iface.ErrNotFound)
+
+		status, body := do(m, "/users/4/restore?debug", `"3"`)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, "deleted user not found", body)
+	}
+
+	// fails if service fails
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().RestoreUser(gomock.Any(), int64(4), int64(3)).Return(fmt.Errorf("opz"))
+
+		status, body := do(m, "/users/4/restore?debug", `"3"`)
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Equal(t, "service failed", body)
+	}
+}
+
 func TestUsersHandle(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -216,7 +511,7 @@ func TestUsersHandle(t *testing.T) {
 		user := &entity.User{ID: 4, Name: "John Doe"}
 		m.EXPECT().
 			FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 3}).
-			Return([]*entity.User{user}, nil)
+			Return([]*entity.User{user}, "", nil)
 
 		r := chi.NewRouter()
 		router.ApplyMiddlewares(r)
@@ -239,6 +534,58 @@ func TestUsersHandle(t *testing.T) {
 		assert.Equal(t, resp.Users[0].Name, user.Name)
 	}
 
+	// links to the next page
+	{
+		m := mock.NewMockService(ctrl)
+
+		user := &entity.User{ID: 4, Name: "John Doe"}
+		m.EXPECT().
+			FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 1, Cursor: iface.EncodeCursor(3)}).
+			Return([]*entity.User{user}, iface.EncodeCursor(4), nil)
+
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Get("/users", rest.ListUsersHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		res, err := http.Get(fmt.Sprintf("%s/users?limit=1&cursor=%s", ts.URL, iface.EncodeCursor(3)))
+		assert.Nil(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusOK)
+
+		var resp struct {
+			Users []*entity.User
+			Next  string
+		}
+		err = json.NewDecoder(res.Body).Decode(&resp)
+		assert.Nil(t, err)
+		res.Body.Close()
+
+		assert.Len(t, resp.Users, 1)
+		assert.Equal(t, "/users?cursor="+iface.EncodeCursor(4)+"&limit=1", resp.Next)
+	}
+
+	// fail if invalid cursor
+	{
+		m := mock.NewMockService(ctrl)
+
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Get("/users", rest.ListUsersHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		res, err := http.Get(fmt.Sprintf("%s/users?debug&cursor=opz", ts.URL))
+		assert.Nil(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusBadRequest)
+
+		b, _ := ioutil.ReadAll(res.Body)
+		assert.Equal(t, string(b), "invalid URL query cursor")
+		res.Body.Close()
+	}
+
 	// fail if invalid limit
 	{
 		m := mock.NewMockService(ctrl)
@@ -259,12 +606,53 @@ func TestUsersHandle(t *testing.T) {
 		res.Body.Close()
 	}
 
+	// include deleted
+	{
+		m := mock.NewMockService(ctrl)
+
+		m.EXPECT().
+			FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 100, IncludeDeleted: true}).
+			Return([]*entity.User{}, "", nil)
+
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Get("/users", rest.ListUsersHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		res, err := http.Get(fmt.Sprintf("%s/users?include_deleted=1", ts.URL))
+		assert.Nil(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusOK)
+		res.Body.Close()
+	}
+
+	// fail if invalid include_deleted
+	{
+		m := mock.NewMockService(ctrl)
+
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Get("/users", rest.ListUsersHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		res, err := http.Get(fmt.Sprintf("%s/users?debug&include_deleted=a", ts.URL))
+		assert.Nil(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusBadRequest)
+
+		b, _ := ioutil.ReadAll(res.Body)
+		assert.Equal(t, string(b), "invalid URL query include_deleted")
+		res.Body.Close()
+	}
+
 	// fails if service fails
 	{
 		m := mock.NewMockService(ctrl)
 
 		user := &entity.User{ID: 4, Name: "John Doe"}
-		m.EXPECT().FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 100}).Return([]*entity.User{user}, fmt.Errorf("not working"))
+		m.EXPECT().FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 100}).Return([]*entity.User{user}, "", fmt.Errorf("not working"))
 
 		r := chi.NewRouter()
 		router.ApplyMiddlewares(r)
@@ -291,7 +679,7 @@ func TestUserHandle(t *testing.T) {
 	{
 		m := mock.NewMockService(ctrl)
 
-		user := &entity.User{ID: 4, Name: "John Doe"}
+		user := &entity.User{ID: 4, Name: "John Doe", Version: 3}
 
 		m.EXPECT().
 			GetUserByID(gomock.Any(), user.ID).
@@ -307,6 +695,7 @@ func TestUserHandle(t *testing.T) {
 		res, err := http.Get(fmt.Sprintf("%s/user/%d", ts.URL, user.ID))
 		assert.Nil(t, err)
 		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `"3"`, res.Header.Get("ETag"))
 
 		var u struct{ User *entity.User }
 		err = json.NewDecoder(res.Body).Decode(&u)
@@ -601,7 +990,7 @@ func TestEmailsHandle(t *testing.T) {
 
 		m.EXPECT().
 			FilterEmails(gomock.Any(), iface.FilterEmails{UserID: user.ID}).
-			Return(emails, nil)
+			Return(emails, "", nil)
 
 		r := chi.NewRouter()
 		router.ApplyMiddlewares(r)
@@ -635,7 +1024,7 @@ func TestEmailsHandle(t *testing.T) {
 
 		m.EXPECT().
 			FilterEmails(gomock.Any(), iface.FilterEmails{UserID: user.ID}).
-			Return(nil, fmt.Errorf("failed"))
+			Return(nil, "", fmt.Errorf("failed"))
 
 		r := chi.NewRouter()
 		router.ApplyMiddlewares(r)
@@ -667,6 +1056,26 @@ func TestEmailsHandle(t *testing.T) {
 		res.Body.Close()
 	}
 
+	// fails if limit is invalid
+	{
+		m := mock.NewMockService(ctrl)
+
+		r := chi.NewRouter()
+		router.ApplyMiddlewares(r)
+		r.Get("/emails", rest.ListEmailsHandle(m))
+
+		ts := httptest.NewServer(r)
+		defer ts.Close()
+
+		res, err := http.Get(fmt.Sprintf("%s/emails?debug&user_id=4&limit=0", ts.URL))
+		assert.Nil(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusBadRequest)
+
+		b, _ := ioutil.ReadAll(res.Body)
+		assert.Equal(t, string(b), "invalid URL query limit")
+		res.Body.Close()
+	}
+
 	// fails if not userID is provided
 	{
 		m := mock.NewMockService(ctrl)
@@ -684,3 +1093,165 @@ func TestEmailsHandle(t *testing.T) {
 		res.Body.Close()
 	}
 }
+
+func TestHandlesWithStorage(t *testing.T) {
+	r := chi.NewRouter()
+	router.ApplyMiddlewares(r)
+	router.ApplyRoute(r, service.New(memory.New()), nil)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	// add user
+	var ru struct {
+		UserID int64 `json:"user_id"`
+	}
+	{
+		res, err := http.Post(fmt.Sprintf("%s/rest/users", ts.URL), "application/json",
+			bytes.NewBufferString("{\"name\":\"John\"}"))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Nil(t, json.NewDecoder(res.Body).Decode(&ru))
+		res.Body.Close()
+	}
+
+	// add email
+	{
+		body := bytes.NewBufferString(fmt.Sprintf("{\"user_id\":%d,\"address\":\"john@example.com\"}", ru.UserID))
+		res, err := http.Post(fmt.Sprintf("%s/rest/emails", ts.URL), "application/json", body)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res.Body.Close()
+	}
+
+	// list emails
+	{
+		res, err := http.Get(fmt.Sprintf("%s/rest/emails?user_id=%d", ts.URL, ru.UserID))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var rEmails struct{ Emails []*entity.Email }
+		assert.Nil(t, json.NewDecoder(res.Body).Decode(&rEmails))
+		res.Body.Close()
+
+		assert.Len(t, rEmails.Emails, 1)
+		assert.Equal(t, "john@example.com", rEmails.Emails[0].Address)
+		assert.Equal(t, ru.UserID, rEmails.Emails[0].UserID)
+	}
+
+	etag := func() string {
+		res, err := http.Get(fmt.Sprintf("%s/rest/users/%d", ts.URL, ru.UserID))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res.Body.Close()
+
+		return res.Header.Get("ETag")
+	}
+
+	// tag is the ETag of the last write
+	var tag string
+	write := func(method, ifMatch, body string) int {
+		req, err := http.NewRequest(method, fmt.Sprintf("%s/rest/users/%d", ts.URL, ru.UserID),
+			bytes.NewBufferString(body))
+		assert.Nil(t, err)
+		req.Header.Set("If-Match", ifMatch)
+
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		res.Body.Close()
+
+		tag = res.Header.Get("ETag")
+		return res.StatusCode
+	}
+
+	// update user; the old ETag is stale afterwards
+	{
+		stale := etag()
+		assert.Equal(t, `"1"`, stale)
+
+		assert.Equal(t, http.StatusOK, write(http.MethodPatch, stale, "{\"name\":\"Jane\"}"))
+		assert.Equal(t, `"2"`, tag)
+		assert.Equal(t, tag, etag())
+
+		assert.Equal(t, http.StatusConflict, write(http.MethodPatch, stale, "{\"name\":\"John\"}"))
+		assert.Equal(t, http.StatusConflict, write(http.MethodDelete, stale, ""))
+	}
+
+	// delete user; its ETag conditions the restore
+	deleted := etag()
+	assert.Equal(t, http.StatusOK, write(http.MethodDelete, deleted, ""))
+	assert.Equal(t, `"3"`, tag)
+
+	// list users
+	{
+		res, err := http.Get(fmt.Sprintf("%s/rest/users", ts.URL))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var resp struct{ Users []*entity.User }
+		assert.Nil(t, json.NewDecoder(res.Body).Decode(&resp))
+		res.Body.Close()
+
+		assert.Len(t, resp.Users, 0)
+	}
+
+	// restore user; its emails come back
+	{
+		restore := func(ifMatch string) int {
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/rest/users/%d/restore", ts.URL, ru.UserID), nil)
+			assert.Nil(t, err)
+			req.Header.Set("If-Match", ifMatch)
+
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			res.Body.Close()
+
+			return res.StatusCode
+		}
+
+		assert.Equal(t, http.StatusConflict, restore(deleted))
+		assert.Equal(t, http.StatusOK, restore(tag))
+		assert.Equal(t, `"4"`, etag())
+
+		res, err := http.Get(fmt.Sprintf("%s/rest/emails?user_id=%d", ts.URL, ru.UserID))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var rEmails struct{ Emails []*entity.Email }
+		assert.Nil(t, json.NewDecoder(res.Body).Decode(&rEmails))
+		res.Body.Close()
+
+		assert.Len(t, rEmails.Emails, 1)
+	}
+
+	// list emails page by page
+	{
+		body := bytes.NewBufferString(fmt.Sprintf("{\"user_id\":%d,\"address\":\"jane@example.com\"}", ru.UserID))
+		res, err := http.Post(fmt.Sprintf("%s/rest/emails", ts.URL), "application/json", body)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res.Body.Close()
+
+		var addresses []string
+		next := fmt.Sprintf("/rest/emails?user_id=%d&limit=1", ru.UserID)
+		for len(next) != 0 {
+			res, err := http.Get(ts.URL + next)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+
+			var rEmails struct {
+				Emails []*entity.Email
+				Next   string
+			}
+			assert.Nil(t, json.NewDecoder(res.Body).Decode(&rEmails))
+			res.Body.Close()
+
+			for _, e := range rEmails.Emails {
+				addresses = append(addresses, e.Address)
+			}
+			next = rEmails.Next
+		}
+
+		assert.Equal(t, []string{"john@example.com", "jane@example.com"}, addresses)
+	}
+}
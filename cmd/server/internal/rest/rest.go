@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
 )
 
 // Fail writes the error message if debug is set.
@@ -22,3 +25,71 @@ func JSON(w http.ResponseWriter, r *http.Request, data interface{}) {
 		Fail(w, r, http.StatusInternalServerError, "could not encode response")
 	}
 }
+
+// ETag sets the entity tag of a versioned resource.
+func ETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// IfMatch reads the version a write is conditioned on; if the If-Match header
+// is missing or is not an entity tag set by ETag, it fails the request.
+func IfMatch(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.Header.Get("If-Match")
+	if len(raw) == 0 {
+		Fail(w, r, http.StatusPreconditionRequired, "missing If-Match")
+		return 0, false
+	}
+
+	tag, err := strconv.Unquote(raw)
+	if err != nil {
+		Fail(w, r, http.StatusBadRequest, "invalid If-Match")
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		Fail(w, r, http.StatusBadRequest, "invalid If-Match")
+		return 0, false
+	}
+
+	return version, true
+}
+
+// IncludeDeleted reads the include_deleted URL query; if it is not a boolean, it fails the request.
+func IncludeDeleted(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	raw := r.URL.Query().Get("include_deleted")
+	if len(raw) == 0 {
+		return false, true
+	}
+
+	include, err := strconv.ParseBool(raw)
+	if err != nil {
+		Fail(w, r, http.StatusBadRequest, "invalid URL query include_deleted")
+		return false, false
+	}
+
+	return include, true
+}
+
+// Cursor reads the cursor URL query; if it is not a cursor returned by a list, it fails the request.
+func Cursor(w http.ResponseWriter, r *http.Request) (string, bool) {
+	cursor := r.URL.Query().Get("cursor")
+	if _, err := iface.DecodeCursor(cursor); err != nil {
+		Fail(w, r, http.StatusBadRequest, "invalid URL query cursor")
+		return "", false
+	}
+
+	return cursor, true
+}
+
+// Page writes a list response; while there is a next cursor, it links to the next page.
+func Page(w http.ResponseWriter, r *http.Request, name string, list interface{}, next string) {
+	data := map[string]interface{}{name: list}
+	if len(next) != 0 {
+		query := r.URL.Query()
+		query.Set("cursor", next)
+		data["next"] = r.URL.Path + "?" + query.Encode()
+	}
+
+	JSON(w, r, data)
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"os"
 	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/rafaelsq/boiler/pkg/log"
@@ -33,3 +35,21 @@ func Recoverer(next http.Handler) http.Handler {
 
 	return http.HandlerFunc(fn)
 }
+
+// Timeout is middleware.Timeout for all but websocket upgrades, which outlive their request.
+func Timeout(timeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		timed := middleware.Timeout(timeout)(next)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.Header.Get("Upgrade"), "websocket") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			timed.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -21,7 +21,7 @@ func ApplyMiddlewares(r chi.Router) {
 	r.Use(middleware.Logger)
 	r.Use(middleware.RedirectSlashes)
 	r.Use(middleware.Compress(flate.BestCompression))
-	r.Use(middleware.Timeout(2 * time.Second))
+	r.Use(Timeout(2 * time.Second))
 
 	r.Use(func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
@@ -38,7 +38,7 @@ func ApplyMiddlewares(r chi.Router) {
 	})
 }
 
-func ApplyRoute(r chi.Router, service iface.Service) {
+func ApplyRoute(r chi.Router, service iface.Service, queries graphql.QueryCache) {
 	// website
 	r.Get("/", website.Handle)
 	r.Get("/favicon.ico", http.NotFound)
@@ -47,7 +47,7 @@ func ApplyRoute(r chi.Router, service iface.Service) {
 	// graphql
 	r.Route("/graphql", func(g chi.Router) {
 		g.Get("/play", graphql.PlayHandle())
-		g.HandleFunc("/query", graphql.QueryHandleFunc(service))
+		g.HandleFunc("/query", graphql.QueryHandleFunc(service, graphql.DefaultLimits, queries))
 	})
 
 	// rest
@@ -55,7 +55,9 @@ func ApplyRoute(r chi.Router, service iface.Service) {
 		r.Get("/users", rest.ListUsersHandle(service))
 		r.Post("/users", rest.AddUserHandle(service))
 		r.Get("/users/{userID:[0-9]+}", rest.GetUserHandle(service))
+		r.Patch("/users/{userID:[0-9]+}", rest.UpdateUserHandle(service))
 		r.Delete("/users/{userID:[0-9]+}", rest.DeleteUserHandle(service))
+		r.Post("/users/{userID:[0-9]+}/restore", rest.RestoreUserHandle(service))
 
 		r.Get("/emails", rest.ListEmailsHandle(service))
 		r.Post("/emails", rest.AddEmailHandle(service))
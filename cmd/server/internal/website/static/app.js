@@ -82,20 +82,21 @@ const handleDeleteUser = (state, {data, err, args}) => {
     if (err) {
         alert('could not remove user')
         console.error(err)
-        return Unlock
+        // the user may have been changed by someone else
+        return [Unlock(state), [d => d(FetchUsers)]]
     }
 
     return Unlock({...state, users: state.users.filter(u => u.id != args)})
 }
-const DeleteUser = (state, user_id) => [
+const DeleteUser = (state, user) => [
     Lock(state),
     [
         _fetchFx,
         {
-            args: user_id,
+            args: user.id,
             action: handleDeleteUser,
-            path: `/rest/users/${user_id}?debug`,
-            options: {method: 'DELETE'},
+            path: `/rest/users/${user.id}?debug`,
+            options: {method: 'DELETE', headers: {'If-Match': `"${user.version}"`}},
         },
     ],
 ]
@@ -160,7 +161,7 @@ const User = (state, user) => h(
         [
             h('header', {className: 'card-header'}, [
                 h('p', {className: 'card-header-title'}, user.name),
-                h('a', {className:'card-header-icon', disabled: state.lock, onclick: [DeleteUser, user.id]},
+                h('a', {className:'card-header-icon', disabled: state.lock, onclick: [DeleteUser, user]},
                     h('span', {className: 'delete'}),
                 ),
             ]),
@@ -3,28 +3,42 @@ package main
 import (
 	"context"
 	"database/sql"
+	"expvar"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	// mariadb
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 
 	"github.com/bradfitz/gomemcache/memcache"
 	"github.com/go-chi/chi"
 	"github.com/rafaelsq/boiler/cmd/server/internal/router"
 	"github.com/rafaelsq/boiler/pkg/cache"
+	"github.com/rafaelsq/boiler/pkg/graphql"
+	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/service"
 	"github.com/rafaelsq/boiler/pkg/storage"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/rafaelsq/boiler/pkg/storage/sqlite"
 )
 
+// newMariaDB connects to dsn, counting the rows an UPDATE matches rather than
+// those it changed, as the storage's conflict checks expect.
 func newMariaDB(dsn string) (*sql.DB, error) {
-	db, err := sql.Open("mysql", dsn)
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ClientFoundRows = true
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
 		return nil, err
 	}
@@ -42,8 +56,75 @@ func newMariaDB(dsn string) (*sql.DB, error) {
 	return db, err
 }
 
+func newStorage(driver, dsn string) (iface.Storage, error) {
+	switch driver {
+	case "mysql":
+		if len(dsn) == 0 {
+			dsn = "root:boiler@tcp(127.0.0.1:3307)/boiler?timeout=5s&parseTime=true&loc=UTC"
+		}
+		db, err := newMariaDB(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return storage.New(db), nil
+	case "sqlite":
+		if len(dsn) == 0 {
+			dsn = "boiler.db"
+		}
+		db, err := sqlite.Open(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return sqlite.New(db), nil
+	case "memory":
+		return memory.New(), nil
+	}
+
+	return nil, fmt.Errorf("unknown storage \"%s\"", driver)
+}
+
+// newQueryCache returns the allow list of the *.graphql documents in dir or, without
+// a dir, the persisted queries kept in memcache and a local LRU.
+func newQueryCache(mc *memcache.Client, dir string) (graphql.QueryCache, error) {
+	if len(dir) != 0 {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+		if err != nil {
+			return nil, err
+		}
+
+		documents := make([]string, 0, len(paths))
+		for _, path := range paths {
+			document, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, string(document))
+		}
+
+		return graphql.NewAllowList(documents...), nil
+	}
+
+	queries, err := graphql.NewLRUQueries(1000)
+	if err != nil {
+		return nil, err
+	}
+
+	return graphql.NewMemcacheQueries(mc, queries), nil
+}
+
 func main() {
 	var port = flag.Int("port", 2000, "")
+	var driver = flag.String("storage", "mysql", "storage backend; mysql, sqlite or memory")
+	var dsn = flag.String("dsn", "", "storage data source name; defaults to the local MariaDB or boiler.db")
+	var allowList = flag.String("allow-list", "", "directory of the only GraphQL documents to run, one per *.graphql file")
+	var userTTL = flag.Duration("cache-user-ttl", cache.DefaultConfig.UserTTL, "how long cached users stay fresh; 0 keeps them until evicted")
+	var emailTTL = flag.Duration("cache-email-ttl", cache.DefaultConfig.EmailTTL, "how long cached emails stay fresh; 0 keeps them until evicted")
+	var stale = flag.Duration("cache-stale", cache.DefaultConfig.Stale, "how long expired entries are served while refreshed in the background; 0 disables it")
+	var refreshes = flag.Int("cache-refreshes", cache.DefaultConfig.Refreshes, "background refreshes run at once")
+	var notFoundTTL = flag.Duration("cache-not-found-ttl", cache.DefaultConfig.NotFoundTTL, "how long missing users are remembered; 0 disables it")
+	var localSize = flag.Int("local-cache-size", 0, "items kept in process in front of memcache; 0 disables it")
+	var localTTL = flag.Duration("local-cache-ttl", 10*time.Second, "how long an item is kept in process")
+	var localCheck = flag.Duration("local-cache-check", time.Second, "how often deletes from other servers are looked for")
 
 	flag.Parse()
 
@@ -58,16 +139,39 @@ func main() {
 
 	mc := memcache.New("127.0.0.1:11211")
 
-	sql, err := newMariaDB("root:boiler@tcp(127.0.0.1:3307)/boiler?timeout=5s&parseTime=true&loc=Local")
+	st, err := newStorage(*driver, *dsn)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	st := cache.New(mc, storage.New(sql))
+	var client cache.Client = mc
+	if *localSize > 0 {
+		local, err := cache.NewLocal(mc, *localSize, *localTTL, *localCheck)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		expvar.Publish("cache", expvar.Func(func() interface{} { return local.Stats() }))
+		client = local
+	}
+
+	st = cache.New(client, st, cache.Config{
+		UserTTL:     *userTTL,
+		EmailTTL:    *emailTTL,
+		Stale:       *stale,
+		Refreshes:   *refreshes,
+		NotFoundTTL: *notFoundTTL,
+	})
+
+	queries, err := newQueryCache(mc, *allowList)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	r := chi.NewRouter()
 	router.ApplyMiddlewares(r)
-	router.ApplyRoute(r, service.New(st))
+	router.ApplyRoute(r, service.New(st), queries)
+	r.Handle("/debug/vars", expvar.Handler())
 
 	// graceful shutdown
 	srv := http.Server{Addr: fmt.Sprintf(":%d", *port), Handler: r}
@@ -1,26 +1,44 @@
 module github.com/rafaelsq/boiler
 
-go 1.12
+go 1.20
 
 require (
 	github.com/99designs/gqlgen v0.10.1
 	github.com/DATA-DOG/go-sqlmock v1.3.3
-	github.com/agnivade/levenshtein v1.0.2 // indirect
 	github.com/bradfitz/gomemcache v0.0.0-20190329173943-551aad21a668
 	github.com/go-chi/chi v4.0.2+incompatible
 	github.com/go-sql-driver/mysql v1.4.1
 	github.com/golang/mock v1.3.1
-	github.com/gorilla/websocket v1.4.1 // indirect
-	github.com/hashicorp/golang-lru v0.5.3 // indirect
-	github.com/mitchellh/mapstructure v1.1.2 // indirect
-	github.com/philhofer/fwd v1.0.0 // indirect
+	github.com/hashicorp/golang-lru v0.5.3
 	github.com/rafaelsq/errors v0.0.0-20190703151832-7a3cfc8d45c9
 	github.com/rs/zerolog v1.15.0
 	github.com/stretchr/testify v1.4.0
 	github.com/tinylib/msgp v1.1.0
 	github.com/vektah/gqlparser v1.1.2
-	golang.org/x/net v0.0.0-20191011234655-491137f69257 // indirect
+	modernc.org/sqlite v1.29.6
+)
+
+require (
+	github.com/agnivade/levenshtein v1.0.2 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/gorilla/websocket v1.4.1 // indirect
+	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
+	github.com/mattn/go-isatty v0.0.16 // indirect
+	github.com/mitchellh/mapstructure v1.1.2 // indirect
+	github.com/ncruces/go-strftime v0.1.9 // indirect
+	github.com/philhofer/fwd v1.0.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	golang.org/x/sys v0.16.0 // indirect
 	google.golang.org/appengine v1.6.2 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 	gopkg.in/yaml.v2 v2.2.4 // indirect
+	modernc.org/gc/v3 v3.0.0-20240107210532-573471604cb6 // indirect
+	modernc.org/libc v1.41.0 // indirect
+	modernc.org/mathutil v1.6.0 // indirect
+	modernc.org/memory v1.7.2 // indirect
+	modernc.org/strutil v1.2.0 // indirect
+	modernc.org/token v1.1.0 // indirect
 )
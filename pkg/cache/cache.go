@@ -1,10 +1,10 @@
 package cache
 
 import (
-	"bytes"
 	"context"
-	"database/sql"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
 	"github.com/rafaelsq/boiler/pkg/entity"
@@ -17,109 +17,321 @@ func userCacheKey(ID int64) string {
 	return fmt.Sprintf("user-%d", ID)
 }
 
-func New(client *memcache.Client, storage iface.Storage) iface.Storage {
-	return &Cache{client, storage}
+// Client is the subset of *memcache.Client used by Cache.
+type Client interface {
+	GetMulti(keys []string) (map[string]*memcache.Item, error)
+	Set(item *memcache.Item) error
+	Add(item *memcache.Item) error
+	Delete(key string) error
+}
+
+// Config sets how long memcache keeps entries; memcache counts whole seconds.
+type Config struct {
+	// UserTTL and EmailTTL are how long users and emails stay fresh; zero keeps
+	// them until they are invalidated or evicted.
+	UserTTL  time.Duration
+	EmailTTL time.Duration
+
+	// Stale is how long past its TTL an entry is still served, while it is refreshed
+	// in the background; zero expires entries at their TTL.
+	Stale time.Duration
+
+	// Refreshes bounds the background refreshes run at once; an entry whose refresh
+	// finds them all busy is refreshed when read again.
+	Refreshes int
+
+	// NotFoundTTL is how long an ID is remembered not to exist; zero does not remember it.
+	NotFoundTTL time.Duration
+}
+
+// DefaultConfig keeps entries, which writes invalidate, for long, and forgets missing
+// IDs shortly, as they are rarely asked for twice.
+var DefaultConfig = Config{
+	UserTTL:     10 * time.Minute,
+	EmailTTL:    10 * time.Minute,
+	Stale:       time.Minute,
+	Refreshes:   4,
+	NotFoundTTL: 30 * time.Second,
+}
+
+func New(client Client, storage iface.Storage, config Config) iface.Storage {
+	refreshes := config.Refreshes
+	if refreshes < 1 {
+		refreshes = 1
+	}
+
+	return &Cache{
+		client:     client,
+		storage:    storage,
+		config:     config,
+		workers:    make(chan struct{}, refreshes),
+		refreshing: map[string]bool{},
+	}
 }
 
 type Cache struct {
-	client  *memcache.Client
+	client  Client
 	storage iface.Storage
+	config  Config
+
+	users flight
+
+	workers    chan struct{}
+	mu         sync.Mutex
+	refreshing map[string]bool
+}
+
+// notFound is cached for the IDs without a user; no entity encodes as a msgp nil.
+var notFound = msgp.AppendNil(nil)
+
+// expiration returns ttl in the seconds memcache counts, rounded up.
+func expiration(ttl time.Duration) int32 {
+	return int32((ttl + time.Second - 1) / time.Second)
 }
 
 // begin transaction
-func (c *Cache) Tx() (*sql.Tx, error) {
-	return c.storage.Tx()
+func (c *Cache) Tx() (iface.Tx, error) {
+	tx, err := c.storage.Tx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: tx, client: c.client}, nil
 }
 
 // user
-func (c *Cache) AddUser(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
-	return c.storage.AddUser(ctx, tx, name)
+// AddUser forgets that the new ID did not exist.
+func (c *Cache) AddUser(ctx context.Context, tx iface.Tx, name string) (int64, error) {
+	ID, err := c.storage.AddUser(ctx, unwrap(tx), name)
+	if err == nil {
+		c.invalidate(tx, userCacheKey(ID))
+	}
+
+	return ID, err
+}
+
+func (c *Cache) UpdateUser(ctx context.Context, tx iface.Tx, userID, version int64, name string) error {
+	err := c.storage.UpdateUser(ctx, unwrap(tx), userID, version, name)
+	if err == nil {
+		c.invalidate(tx, userCacheKey(userID))
+	}
+
+	return err
+}
+
+func (c *Cache) DeleteUser(ctx context.Context, tx iface.Tx, userID, version int64) error {
+	err := c.storage.DeleteUser(ctx, unwrap(tx), userID, version)
+	if err == nil {
+		c.invalidate(tx, userCacheKey(userID))
+	}
+
+	return err
 }
 
-func (c *Cache) DeleteUser(ctx context.Context, tx *sql.Tx, userID int64) error {
-	_ = c.client.Delete(userCacheKey(userID))
-	return c.storage.DeleteUser(ctx, tx, userID)
+func (c *Cache) RestoreUser(ctx context.Context, tx iface.Tx, userID, version int64) error {
+	err := c.storage.RestoreUser(ctx, unwrap(tx), userID, version)
+	if err == nil {
+		c.invalidate(tx, userCacheKey(userID))
+	}
+
+	return err
 }
 
-func (c *Cache) FilterUsersID(ctx context.Context, filter iface.FilterUsers) ([]int64, error) {
+func (c *Cache) FilterUsersID(ctx context.Context, filter iface.FilterUsers) ([]int64, string, error) {
 	return c.storage.FilterUsersID(ctx, filter)
 }
 
-func (c *Cache) FetchUsers(ctx context.Context, IDs ...int64) ([]*entity.User, error) {
-	keys := make([]string, 0, len(IDs))
-	for _, ID := range IDs {
-		keys = append(keys, fmt.Sprintf("user-%d", ID))
+func (c *Cache) CountUsers(ctx context.Context, filter iface.FilterUsers) (int64, error) {
+	return c.storage.CountUsers(ctx, filter)
+}
+
+// FetchUsers caches deleted users too; they are filtered out here unless includeDeleted.
+func (c *Cache) FetchUsers(ctx context.Context, includeDeleted bool, IDs ...int64) ([]*entity.User, error) {
+	musers, missing, stale := c.cachedUsers(IDs)
+	if len(stale) != 0 {
+		c.refresh(stale, userCacheKey, c.refreshUsers)
 	}
 
 	IDsToFetch := make([]int64, 0, len(IDs))
-	hit := map[int64]bool{}
-	musers := map[int64]*entity.User{}
-	if items, err := c.client.GetMulti(keys); err != nil {
-		log.Log(err)
-	} else {
-		for _, item := range items {
-			var user entity.User
-			if err := msgp.Decode(bytes.NewBuffer(item.Value), &user); err != nil {
-				log.Log(err)
-				continue
-			}
-
-			musers[user.ID] = &user
-			hit[user.ID] = true
-		}
-		for _, ID := range IDs {
-			if _, has := hit[ID]; !has {
-				IDsToFetch = append(IDsToFetch, ID)
-			}
+	for _, ID := range IDs {
+		if _, has := musers[ID]; !has && !missing[ID] {
+			IDsToFetch = append(IDsToFetch, ID)
 		}
 	}
 
 	if len(IDsToFetch) != 0 {
-		dbusers, err := c.storage.FetchUsers(ctx, IDsToFetch...)
+		// concurrent misses of an ID wait for the one that rebuilds it
+		dbusers, err := c.users.do(ctx, IDsToFetch, c.rebuildUsers)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, user := range dbusers {
-			var buf bytes.Buffer
-			err := msgp.Encode(&buf, user)
-			if err != nil {
-				log.Log(err)
-				continue
-			}
-
-			err = c.client.Set(&memcache.Item{
-				Key:   fmt.Sprintf("user-%d", user.ID),
-				Value: buf.Bytes(),
-			})
-			if err != nil {
-				log.Log(err)
-			}
-			musers[user.ID] = user
+		for ID, user := range dbusers {
+			musers[ID] = user.(*entity.User)
 		}
 	}
 
 	users := make([]*entity.User, 0, len(IDs))
 	for _, ID := range IDs {
-		users = append(users, musers[ID])
+		if user, has := musers[ID]; has && (includeDeleted || user.DeletedAt == nil) {
+			users = append(users, user)
+		}
 	}
 
 	return users, nil
 }
 
-// email
-func (c *Cache) AddEmail(ctx context.Context, tx *sql.Tx, userID int64, address string) (int64, error) {
-	return c.storage.AddEmail(ctx, tx, userID, address)
+// cachedUsers returns the users of IDs memcache has, the IDs it knows have none and
+// those of the users that are stale.
+func (c *Cache) cachedUsers(IDs []int64) (map[int64]*entity.User, map[int64]bool, []int64) {
+	keys := make([]string, 0, len(IDs))
+	for _, ID := range IDs {
+		keys = append(keys, userCacheKey(ID))
+	}
+
+	musers := make(map[int64]*entity.User, len(IDs))
+	missing := map[int64]bool{}
+	var stale []int64
+	items, err := c.client.GetMulti(keys)
+	if err != nil {
+		log.Log(err)
+		return musers, missing, stale
+	}
+
+	for i, key := range keys {
+		item, has := items[key]
+		if !has {
+			continue
+		}
+
+		payload, old, err := open(item.Value)
+		if err != nil {
+			log.Log(err)
+			continue
+		}
+
+		if msgp.IsNil(payload) {
+			missing[IDs[i]] = true
+			continue
+		}
+
+		var user entity.User
+		if _, err := user.UnmarshalMsg(payload); err != nil {
+			log.Log(err)
+			continue
+		}
+
+		inUTC(&user)
+		musers[user.ID] = &user
+		if old {
+			stale = append(stale, user.ID)
+		}
+	}
+
+	return musers, missing, stale
 }
 
-func (c *Cache) DeleteEmail(ctx context.Context, tx *sql.Tx, emailID int64) error {
-	return c.storage.DeleteEmail(ctx, tx, emailID)
+// rebuildUsers fetches the users of IDs from storage and caches them. Of the IDs
+// whose lease another server holds, it waits for the users that server caches and
+// only fetches those still missing once the wait is over.
+func (c *Cache) rebuildUsers(ctx context.Context, IDs []int64) (map[int64]interface{}, error) {
+	rebuild, held, others := c.lease(IDs, userCacheKey)
+
+	users := make(map[int64]interface{}, len(IDs))
+	err := c.fetchUsers(ctx, rebuild, users)
+	c.release(held)
+	if err != nil {
+		return nil, err
+	}
+
+	for try := 0; try < leaseTries && len(others) != 0; try++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(leaseWait):
+		}
+
+		cached, missing, _ := c.cachedUsers(others)
+		for ID, user := range cached {
+			users[ID] = user
+		}
+
+		waiting := others[:0]
+		for _, ID := range others {
+			if _, has := users[ID]; !has && !missing[ID] {
+				waiting = append(waiting, ID)
+			}
+		}
+		others = waiting
+	}
+
+	if err := c.fetchUsers(ctx, others, users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
 }
 
-func (c *Cache) DeleteEmailsByUserID(ctx context.Context, tx *sql.Tx, userID int64) error {
-	return c.storage.DeleteEmailsByUserID(ctx, tx, userID)
+// fetchUsers fetches the users of IDs from storage into users and caches them, and
+// that the others do not exist.
+func (c *Cache) fetchUsers(ctx context.Context, IDs []int64, users map[int64]interface{}) error {
+	if len(IDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(IDs))
+	for _, ID := range IDs {
+		keys = append(keys, userCacheKey(ID))
+	}
+	settle := c.guard(keys)
+	defer settle()
+
+	dbusers, err := c.storage.FetchUsers(ctx, true, IDs...)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range dbusers {
+		payload, err := user.MarshalMsg(nil)
+		if err != nil {
+			log.Log(err)
+			continue
+		}
+
+		c.set(userCacheKey(user.ID), payload, c.config.UserTTL, c.config.Stale)
+		users[user.ID] = user
+	}
+
+	if c.config.NotFoundTTL <= 0 {
+		return nil
+	}
+
+	for _, ID := range IDs {
+		if _, has := users[ID]; !has {
+			c.set(userCacheKey(ID), notFound, c.config.NotFoundTTL, 0)
+		}
+	}
+
+	return nil
 }
 
-func (c *Cache) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, error) {
-	return c.storage.FilterEmails(ctx, filter)
+// refreshUsers rebuilds the stale users of IDs whose lease no other server holds.
+func (c *Cache) refreshUsers(ctx context.Context, IDs []int64) {
+	rebuild, held, _ := c.lease(IDs, userCacheKey)
+	defer c.release(held)
+
+	if err := c.fetchUsers(ctx, rebuild, map[int64]interface{}{}); err != nil {
+		log.Log(err)
+	}
+}
+
+// inUTC puts back the location of the user's times, which msgp decodes as Local;
+// cached users then equal those the storage returns.
+func inUTC(user *entity.User) {
+	user.Created = user.Created.UTC()
+	user.Updated = user.Updated.UTC()
+	if user.DeletedAt != nil {
+		deletedAt := user.DeletedAt.UTC()
+		user.DeletedAt = &deletedAt
+	}
 }
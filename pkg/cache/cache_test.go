@@ -0,0 +1,553 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rafaelsq/boiler/pkg/cache"
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/rafaelsq/boiler/pkg/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// client is an in-process cache.Client.
+type client struct {
+	mu          sync.Mutex
+	items       map[string][]byte
+	expirations map[string]int32
+	err         error
+}
+
+func newClient() *client {
+	return &client{items: map[string][]byte{}, expirations: map[string]int32{}}
+}
+
+func (c *client) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	items := map[string]*memcache.Item{}
+	for _, key := range keys {
+		if value, has := c.items[key]; has {
+			items[key] = &memcache.Item{Key: key, Value: value}
+		}
+	}
+
+	return items, nil
+}
+
+func (c *client) Set(item *memcache.Item) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[item.Key] = item.Value
+	c.expirations[item.Key] = item.Expiration
+	return c.err
+}
+
+func (c *client) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, has := c.items[key]; !has {
+		return memcache.ErrCacheMiss
+	}
+
+	delete(c.items, key)
+	return c.err
+}
+
+func (c *client) Add(item *memcache.Item) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, has := c.items[item.Key]; has {
+		return memcache.ErrNotStored
+	}
+
+	c.items[item.Key] = item.Value
+	return c.err
+}
+
+func (c *client) Increment(key string, delta uint64) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, has := c.items[key]
+	if !has {
+		return 0, memcache.ErrCacheMiss
+	}
+
+	n, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	n += delta
+	c.items[key] = []byte(strconv.FormatUint(n, 10))
+	return n, c.err
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) iface.Storage {
+		return cache.New(newClient(), memory.New(), cache.DefaultConfig)
+	})
+}
+
+func TestFetchUsers(t *testing.T) {
+	ctx := context.Background()
+
+	mc := newClient()
+	st := memory.New()
+	c := cache.New(mc, st, cache.DefaultConfig)
+
+	tx, err := st.Tx()
+	assert.Nil(t, err)
+	userID, err := st.AddUser(ctx, tx, "John")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	// miss fills the cache
+	{
+		users, err := c.FetchUsers(ctx, false, userID, userID+1)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, "John", users[0].Name)
+
+		_, has := mc.items[fmt.Sprintf("user-%d", userID)]
+		assert.True(t, has)
+	}
+
+	// hit returns the stored user exactly
+	{
+		stored, err := st.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+
+		users, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Equal(t, stored, users)
+		assert.Equal(t, time.UTC, users[0].Created.Location())
+	}
+
+	// falls back to storage if the cache fails
+	{
+		mc.err = fmt.Errorf("memcache down")
+
+		users, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, userID, users[0].ID)
+	}
+
+	// deleted users stay cached but are hidden unless included
+	{
+		mc.err = nil
+
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, c.DeleteUser(ctx, tx, userID, 1))
+		assert.Nil(t, tx.Commit())
+
+		users, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		_, has := mc.items[fmt.Sprintf("user-%d", userID)]
+		assert.True(t, has)
+
+		users, err = c.FetchUsers(ctx, true, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+	}
+}
+
+func TestFetchUsersNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	st, userID := newGated(t)
+	close(st.open)
+
+	mc := newClient()
+	c := cache.New(mc, st, cache.Config{NotFoundTTL: 1500 * time.Millisecond})
+
+	missingID := userID + 1
+	key := fmt.Sprintf("user-%d", missingID)
+
+	// remembers a missing ID for the TTL
+	{
+		for i := 0; i < 2; i++ {
+			users, err := c.FetchUsers(ctx, false, userID, missingID)
+			assert.Nil(t, err)
+			assert.Len(t, users, 1)
+		}
+		assert.Equal(t, int64(1), st.calls)
+
+		// never stale, around a msgp nil
+		assert.Equal(t, []byte{0x92, 0x00, 0xc0}, mc.items[key])
+		assert.Equal(t, int32(2), mc.expirations[key])
+		assert.Equal(t, int32(0), mc.expirations[fmt.Sprintf("user-%d", userID)])
+	}
+
+	// AddUser forgets it after commit
+	{
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		ID, err := c.AddUser(ctx, tx, "Jane")
+		assert.Nil(t, err)
+		require.Equal(t, missingID, ID)
+
+		_, has := mc.items[key]
+		assert.True(t, has)
+
+		assert.Nil(t, tx.Commit())
+
+		users, err := c.FetchUsers(ctx, false, missingID)
+		assert.Nil(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "Jane", users[0].Name)
+	}
+
+	// does not remember it without a TTL
+	{
+		c := cache.New(mc, st, cache.Config{})
+		users, err := c.FetchUsers(ctx, false, missingID+1)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		_, has := mc.items[fmt.Sprintf("user-%d", missingID+1)]
+		assert.False(t, has)
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	ctx := context.Background()
+
+	mc := newClient()
+	c := cache.New(mc, memory.New(), cache.DefaultConfig)
+
+	tx, err := c.Tx()
+	assert.Nil(t, err)
+	userID, err := c.AddUser(ctx, tx, "John")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	key := fmt.Sprintf("user-%d", userID)
+
+	// keeps the key if rolled back
+	{
+		_, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, c.UpdateUser(ctx, tx, userID, 1, "Jane"))
+		assert.Nil(t, tx.Rollback())
+
+		_, has := mc.items[key]
+		assert.True(t, has)
+	}
+
+	// deletes the key only after commit
+	{
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, c.UpdateUser(ctx, tx, userID, 1, "Jane"))
+
+		_, has := mc.items[key]
+		assert.True(t, has)
+
+		assert.Nil(t, tx.Commit())
+
+		_, has = mc.items[key]
+		assert.False(t, has)
+	}
+}
+
+// slow holds the users a FetchUsers read until release is closed, once read is closed.
+type slow struct {
+	iface.Storage
+
+	read, release chan struct{}
+}
+
+func (s *slow) FetchUsers(ctx context.Context, includeDeleted bool, IDs ...int64) ([]*entity.User, error) {
+	users, err := s.Storage.FetchUsers(ctx, includeDeleted, IDs...)
+	if s.read != nil {
+		close(s.read)
+		<-s.release
+		s.read = nil
+	}
+
+	return users, err
+}
+
+func TestFetchUsersRacingCommit(t *testing.T) {
+	ctx := context.Background()
+
+	mc := newClient()
+	st := &slow{Storage: memory.New()}
+	c := cache.New(mc, st, cache.DefaultConfig)
+
+	tx, err := c.Tx()
+	require.Nil(t, err)
+	userID, err := c.AddUser(ctx, tx, "John")
+	require.Nil(t, err)
+	require.Nil(t, tx.Commit())
+
+	// a fetch that read the user before a commit does not cache it after
+	st.read, st.release = make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		users, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Equal(t, "John", users[0].Name)
+	}()
+
+	<-st.read
+	tx, err = c.Tx()
+	require.Nil(t, err)
+	require.Nil(t, c.UpdateUser(ctx, tx, userID, 1, "Jane"))
+	require.Nil(t, tx.Commit())
+
+	close(st.release)
+	<-done
+
+	users, err := c.FetchUsers(ctx, false, userID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Jane", users[0].Name)
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+
+	mc := newClient()
+	c := cache.New(mc, memory.New(), cache.DefaultConfig)
+
+	tx, err := c.Tx()
+	assert.Nil(t, err)
+	userID, err := c.AddUser(ctx, tx, "John")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	key := fmt.Sprintf("user-%d", userID)
+
+	// keeps the key if rolled back
+	{
+		_, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, c.DeleteUser(ctx, tx, userID, 1))
+		assert.Nil(t, tx.Rollback())
+
+		_, has := mc.items[key]
+		assert.True(t, has)
+
+		users, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+	}
+
+	// deletes the key only after commit, dropping what reads cached meanwhile
+	{
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, c.DeleteUser(ctx, tx, userID, 1))
+
+		_, has := mc.items[key]
+		assert.True(t, has)
+
+		mc.items = map[string][]byte{}
+		users, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+
+		assert.Nil(t, tx.Commit())
+
+		_, has = mc.items[key]
+		assert.False(t, has)
+
+		users, err = c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+	}
+
+	// fails without deleting the key
+	{
+		_, err := c.FetchUsers(ctx, true, userID)
+		assert.Nil(t, err)
+
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.NotNil(t, c.DeleteUser(ctx, tx, userID, 1))
+		assert.Nil(t, tx.Commit())
+
+		_, has := mc.items[key]
+		assert.True(t, has)
+	}
+}
+
+func TestFilterEmails(t *testing.T) {
+	ctx := context.Background()
+
+	mc := newClient()
+	st := memory.New()
+	c := cache.New(mc, st, cache.DefaultConfig)
+
+	tx, err := st.Tx()
+	assert.Nil(t, err)
+	userID, err := st.AddUser(ctx, tx, "John")
+	assert.Nil(t, err)
+	emailID, err := st.AddEmail(ctx, tx, userID, "john@example.com")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	listKey := fmt.Sprintf("user-emails-%d", userID)
+	emailKey := fmt.Sprintf("email-%d", emailID)
+
+	// miss fills the cache
+	{
+		emails, _, err := c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 1)
+
+		_, has := mc.items[listKey]
+		assert.True(t, has)
+
+		emails, _, err = c.FilterEmails(ctx, iface.FilterEmails{EmailID: emailID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 1)
+
+		_, has = mc.items[emailKey]
+		assert.True(t, has)
+	}
+
+	// hit returns the stored emails exactly
+	{
+		stored, _, err := st.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+
+		emails, _, err := c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Equal(t, stored, emails)
+
+		pages, err := c.FilterEmailsByUserIDs(ctx, iface.FilterEmails{}, []int64{userID})
+		assert.Nil(t, err)
+		assert.Equal(t, stored, pages[userID].Emails)
+	}
+
+	// falls back to storage if the cache fails
+	{
+		mc.err = fmt.Errorf("memcache down")
+
+		emails, _, err := c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 1)
+
+		// what was set while marks could not be read is not trusted
+		mc.err = nil
+		_, has := mc.items[listKey]
+		assert.False(t, has)
+
+		_, _, err = c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+	}
+
+	// AddEmail deletes the list only after commit
+	{
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		_, err = c.AddEmail(ctx, tx, userID, "jane@example.com")
+		assert.Nil(t, err)
+
+		_, has := mc.items[listKey]
+		assert.True(t, has)
+
+		assert.Nil(t, tx.Commit())
+
+		_, has = mc.items[listKey]
+		assert.False(t, has)
+
+		emails, _, err := c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 2)
+	}
+
+	// DeleteEmail keeps the keys if rolled back
+	{
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, c.DeleteEmail(ctx, tx, emailID))
+		assert.Nil(t, tx.Rollback())
+
+		_, has := mc.items[listKey]
+		assert.True(t, has)
+		_, has = mc.items[emailKey]
+		assert.True(t, has)
+	}
+
+	// DeleteEmail deletes the email and its user's list after commit
+	{
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, c.DeleteEmail(ctx, tx, emailID))
+		assert.Nil(t, tx.Commit())
+
+		_, has := mc.items[listKey]
+		assert.False(t, has)
+		_, has = mc.items[emailKey]
+		assert.False(t, has)
+
+		emails, _, err := c.FilterEmails(ctx, iface.FilterEmails{EmailID: emailID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 0)
+
+		emails, _, err = c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 1)
+	}
+
+	// DeleteEmailsByUserID deletes the list and each email after commit
+	{
+		emails, _, err := c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		require.Len(t, emails, 1)
+		otherKey := fmt.Sprintf("email-%d", emails[0].ID)
+
+		_, _, err = c.FilterEmails(ctx, iface.FilterEmails{EmailID: emails[0].ID})
+		assert.Nil(t, err)
+
+		tx, err := c.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, c.DeleteEmailsByUserID(ctx, tx, userID))
+
+		_, has := mc.items[otherKey]
+		assert.True(t, has)
+
+		assert.Nil(t, tx.Commit())
+
+		_, has = mc.items[listKey]
+		assert.False(t, has)
+		_, has = mc.items[otherKey]
+		assert.False(t, has)
+
+		emails, _, err = c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 0)
+	}
+}
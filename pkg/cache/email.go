@@ -0,0 +1,460 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/log"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// emailsFetchLimit is the page size the whole list of a user's emails is fetched with.
+const emailsFetchLimit = 1000
+
+func emailCacheKey(ID int64) string {
+	return fmt.Sprintf("email-%d", ID)
+}
+
+func userEmailsCacheKey(userID int64) string {
+	return fmt.Sprintf("user-emails-%d", userID)
+}
+
+func (c *Cache) AddEmail(ctx context.Context, tx iface.Tx, userID int64, address string) (int64, error) {
+	ID, err := c.storage.AddEmail(ctx, unwrap(tx), userID, address)
+	if err == nil {
+		c.invalidate(tx, userEmailsCacheKey(userID))
+	}
+
+	return ID, err
+}
+
+func (c *Cache) DeleteEmail(ctx context.Context, tx iface.Tx, emailID int64) error {
+	// the email's user, whose list holds it too
+	email, err := c.email(ctx, emailID)
+	if err != nil {
+		return err
+	}
+
+	err = c.storage.DeleteEmail(ctx, unwrap(tx), emailID)
+	if err == nil {
+		keys := []string{emailCacheKey(emailID)}
+		if email != nil {
+			keys = append(keys, userEmailsCacheKey(email.UserID))
+		}
+		c.invalidate(tx, keys...)
+	}
+
+	return err
+}
+
+func (c *Cache) DeleteEmailsByUserID(ctx context.Context, tx iface.Tx, userID int64) error {
+	// the user's emails, each cached by ID too
+	lists, err := c.userEmails(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = c.storage.DeleteEmailsByUserID(ctx, unwrap(tx), userID)
+	if err == nil {
+		keys := []string{userEmailsCacheKey(userID)}
+		for _, email := range lists[userID] {
+			keys = append(keys, emailCacheKey(email.ID))
+		}
+		c.invalidate(tx, keys...)
+	}
+
+	return err
+}
+
+// FilterEmails pages the cached email, or the cached list of the user's emails, as the storage would.
+func (c *Cache) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, string, error) {
+	var emails []*entity.Email
+	if filter.EmailID > 0 {
+		email, err := c.email(ctx, filter.EmailID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if email != nil {
+			emails = []*entity.Email{email}
+		}
+	} else {
+		lists, err := c.userEmails(ctx, filter.UserID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		emails = lists[filter.UserID]
+	}
+
+	hidden, err := c.hiddenUsers(ctx, filter.IncludeDeleted, emails)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return page(visible(emails, filter.IncludeDeleted, hidden), filter)
+}
+
+func (c *Cache) CountEmails(ctx context.Context, filter iface.FilterEmails) (int64, error) {
+	return c.storage.CountEmails(ctx, filter)
+}
+
+// FilterEmailsByUserIDs pages the cached lists of the users' emails as the storage would.
+func (c *Cache) FilterEmailsByUserIDs(ctx context.Context, filter iface.FilterEmails, userIDs []int64) (map[int64]*iface.EmailsPage, error) {
+	pages := make(map[int64]*iface.EmailsPage, len(userIDs))
+	if len(userIDs) == 0 {
+		return pages, nil
+	}
+
+	lists, err := c.userEmails(ctx, userIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []*entity.Email
+	for _, emails := range lists {
+		all = append(all, emails...)
+	}
+
+	hidden, err := c.hiddenUsers(ctx, filter.IncludeDeleted, all)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range userIDs {
+		emails, next, err := page(visible(lists[userID], filter.IncludeDeleted, hidden), filter)
+		if err != nil {
+			return nil, err
+		}
+
+		pages[userID] = &iface.EmailsPage{Emails: emails, Next: next}
+	}
+
+	return pages, nil
+}
+
+// email returns the email, deleted or not, or nil if there is none.
+func (c *Cache) email(ctx context.Context, ID int64) (*entity.Email, error) {
+	key := emailCacheKey(ID)
+	if items, err := c.client.GetMulti([]string{key}); err != nil {
+		log.Log(err)
+	} else if item, has := items[key]; has {
+		var email entity.Email
+		payload, stale, err := open(item.Value)
+		if err == nil {
+			_, err = email.UnmarshalMsg(payload)
+		}
+
+		if err != nil {
+			log.Log(err)
+		} else {
+			if stale {
+				c.refresh([]int64{ID}, emailCacheKey, c.refreshEmail)
+			}
+
+			emailInUTC(&email)
+			return &email, nil
+		}
+	}
+
+	return c.fetchEmail(ctx, ID)
+}
+
+// fetchEmail fetches the email from storage and caches it.
+func (c *Cache) fetchEmail(ctx context.Context, ID int64) (*entity.Email, error) {
+	settle := c.guard([]string{emailCacheKey(ID)})
+	defer settle()
+
+	emails, _, err := c.storage.FilterEmails(ctx, iface.FilterEmails{EmailID: ID, IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	if payload, err := emails[0].MarshalMsg(nil); err != nil {
+		log.Log(err)
+	} else {
+		c.set(emailCacheKey(ID), payload, c.config.EmailTTL, c.config.Stale)
+	}
+
+	return emails[0], nil
+}
+
+func (c *Cache) refreshEmail(ctx context.Context, IDs []int64) {
+	for _, ID := range IDs {
+		if _, err := c.fetchEmail(ctx, ID); err != nil {
+			log.Log(err)
+		}
+	}
+}
+
+// userEmails returns, by user ID, the lists of all the users' emails, deleted ones too, in ID order.
+func (c *Cache) userEmails(ctx context.Context, userIDs ...int64) (map[int64][]*entity.Email, error) {
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, userEmailsCacheKey(userID))
+	}
+
+	lists := make(map[int64][]*entity.Email, len(userIDs))
+	var stale []int64
+	if items, err := c.client.GetMulti(keys); err != nil {
+		log.Log(err)
+	} else {
+		for i, key := range keys {
+			item, has := items[key]
+			if !has {
+				continue
+			}
+
+			payload, old, err := open(item.Value)
+			if err != nil {
+				log.Log(err)
+				continue
+			}
+
+			emails, err := decodeEmails(payload)
+			if err != nil {
+				log.Log(err)
+				continue
+			}
+
+			lists[userIDs[i]] = emails
+			if old {
+				stale = append(stale, userIDs[i])
+			}
+		}
+	}
+
+	if len(stale) != 0 {
+		c.refresh(stale, userEmailsCacheKey, c.refreshUserEmails)
+	}
+
+	IDsToFetch := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if _, has := lists[userID]; !has {
+			IDsToFetch = append(IDsToFetch, userID)
+		}
+	}
+
+	if len(IDsToFetch) == 0 {
+		return lists, nil
+	}
+
+	fetched, err := c.fetchUserEmails(ctx, IDsToFetch)
+	if err != nil {
+		return nil, err
+	}
+
+	for userID, emails := range fetched {
+		lists[userID] = emails
+	}
+
+	return lists, nil
+}
+
+// fetchUserEmails fetches the lists of all the users' emails from storage and caches them.
+func (c *Cache) fetchUserEmails(ctx context.Context, userIDs []int64) (map[int64][]*entity.Email, error) {
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, userEmailsCacheKey(userID))
+	}
+	settle := c.guard(keys)
+	defer settle()
+
+	pages, err := c.storage.FilterEmailsByUserIDs(ctx, iface.FilterEmails{
+		Limit:          emailsFetchLimit,
+		IncludeDeleted: true,
+	}, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make(map[int64][]*entity.Email, len(userIDs))
+	for _, userID := range userIDs {
+		var emails []*entity.Email
+		var next string
+		if p, has := pages[userID]; has {
+			emails, next = p.Emails, p.Next
+		}
+
+		for next != "" {
+			var more []*entity.Email
+			more, next, err = c.storage.FilterEmails(ctx, iface.FilterEmails{
+				UserID:         userID,
+				Limit:          emailsFetchLimit,
+				Cursor:         next,
+				IncludeDeleted: true,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			emails = append(emails, more...)
+		}
+
+		if payload, err := encodeEmails(emails); err != nil {
+			log.Log(err)
+		} else {
+			c.set(userEmailsCacheKey(userID), payload, c.config.EmailTTL, c.config.Stale)
+		}
+
+		lists[userID] = emails
+	}
+
+	return lists, nil
+}
+
+func (c *Cache) refreshUserEmails(ctx context.Context, userIDs []int64) {
+	if _, err := c.fetchUserEmails(ctx, userIDs); err != nil {
+		log.Log(err)
+	}
+}
+
+// hiddenUsers returns the deleted users among those of emails, whose emails are
+// hidden unless includeDeleted.
+func (c *Cache) hiddenUsers(ctx context.Context, includeDeleted bool, emails []*entity.Email) (map[int64]bool, error) {
+	hidden := map[int64]bool{}
+	if includeDeleted || len(emails) == 0 {
+		return hidden, nil
+	}
+
+	userIDs := make([]int64, 0, len(emails))
+	seen := map[int64]bool{}
+	for _, email := range emails {
+		if !seen[email.UserID] {
+			seen[email.UserID] = true
+			userIDs = append(userIDs, email.UserID)
+		}
+	}
+
+	users, err := c.FetchUsers(ctx, true, userIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if user.DeletedAt != nil {
+			hidden[user.ID] = true
+		}
+	}
+
+	return hidden, nil
+}
+
+// visible returns the emails a filter matches, unless includeDeleted, those not
+// deleted whose user is not hidden.
+func visible(emails []*entity.Email, includeDeleted bool, hidden map[int64]bool) []*entity.Email {
+	if includeDeleted {
+		return emails
+	}
+
+	matches := make([]*entity.Email, 0, len(emails))
+	for _, email := range emails {
+		if email.DeletedAt == nil && !hidden[email.UserID] {
+			matches = append(matches, email)
+		}
+	}
+
+	return matches
+}
+
+// page returns the page of emails, in ID order, the filter's cursor and limit select,
+// and the cursor of the following one.
+func page(emails []*entity.Email, filter iface.FilterEmails) ([]*entity.Email, string, error) {
+	limit := iface.FilterEmailsDefaultLimit
+	if filter.Limit != 0 {
+		limit = filter.Limit
+	}
+
+	bound, err := iface.DecodeCursor(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// rows are listed from the cursor on, in the paging direction
+	rows := make([]*entity.Email, 0, len(emails))
+	if !filter.Backward {
+		for _, email := range emails {
+			if email.ID > bound {
+				rows = append(rows, email)
+			}
+		}
+	} else {
+		for i := len(emails) - 1; i >= 0; i-- {
+			if bound == 0 || emails[i].ID < bound {
+				rows = append(rows, emails[i])
+			}
+		}
+	}
+
+	var next string
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		next = iface.EncodeCursor(rows[len(rows)-1].ID)
+	}
+
+	if filter.Backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	return rows, next, nil
+}
+
+// encodeEmails encodes a list of emails as a msgp array.
+func encodeEmails(emails []*entity.Email) ([]byte, error) {
+	var buf bytes.Buffer
+	w := msgp.NewWriter(&buf)
+	if err := w.WriteArrayHeader(uint32(len(emails))); err != nil {
+		return nil, err
+	}
+
+	for _, email := range emails {
+		if err := email.EncodeMsg(w); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeEmails(value []byte) ([]*entity.Email, error) {
+	r := msgp.NewReader(bytes.NewReader(value))
+	n, err := r.ReadArrayHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]*entity.Email, 0, n)
+	for i := uint32(0); i < n; i++ {
+		var email entity.Email
+		if err := email.DecodeMsg(r); err != nil {
+			return nil, err
+		}
+
+		emailInUTC(&email)
+		emails = append(emails, &email)
+	}
+
+	return emails, nil
+}
+
+// emailInUTC puts back the location of the email's times, which msgp decodes as Local.
+func emailInUTC(email *entity.Email) {
+	email.Created = email.Created.UTC()
+	if email.DeletedAt != nil {
+		deletedAt := email.DeletedAt.UTC()
+		email.DeletedAt = &deletedAt
+	}
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+const (
+	// leaseExpiration is how long, in seconds, a server that dies rebuilding holds a lease
+	leaseExpiration = 1
+
+	// leaseWait and leaseTries are how often and how many times a server waiting for
+	// another to rebuild an entry looks for it
+	leaseWait  = 10 * time.Millisecond
+	leaseTries = 10
+
+	// rebuildTimeout bounds a rebuild shared by concurrent misses
+	rebuildTimeout = 10 * time.Second
+
+	// leaseConcurrency bounds the leases taken or given back at once, each a round trip
+	leaseConcurrency = 16
+)
+
+// flight coalesces the concurrent rebuilds of the same IDs within a process.
+type flight struct {
+	mu    sync.Mutex
+	calls map[int64]*call
+}
+
+type call struct {
+	done  chan struct{}
+	value interface{}
+	found bool
+	err   error
+}
+
+// do returns, by ID, the values fn finds for IDs; fn is called with the IDs no other
+// call is rebuilding, and the others are waited for. As the calls waiting for it
+// share its result, fn runs on a context of its own, bounded by rebuildTimeout,
+// and ctx only ends the wait of this call.
+func (f *flight) do(ctx context.Context, IDs []int64, fn func(context.Context, []int64) (map[int64]interface{}, error)) (map[int64]interface{}, error) {
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = map[int64]*call{}
+	}
+
+	mine := map[int64]*call{}
+	waits := map[int64]*call{}
+	lead := make([]int64, 0, len(IDs))
+	for _, ID := range IDs {
+		if _, has := mine[ID]; has {
+			continue
+		}
+
+		if cl, has := f.calls[ID]; has {
+			waits[ID] = cl
+			continue
+		}
+
+		cl := &call{done: make(chan struct{})}
+		f.calls[ID] = cl
+		mine[ID] = cl
+		lead = append(lead, ID)
+	}
+	f.mu.Unlock()
+
+	if len(lead) != 0 {
+		go f.lead(mine, lead, fn)
+
+		for ID, cl := range mine {
+			waits[ID] = cl
+		}
+	}
+
+	values := make(map[int64]interface{}, len(IDs))
+	for ID, cl := range waits {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-cl.done:
+		}
+
+		if cl.err != nil {
+			return nil, cl.err
+		}
+
+		if cl.found {
+			values[ID] = cl.value
+		}
+	}
+
+	return values, nil
+}
+
+// lead calls fn and hands its result to the calls waiting for mine.
+func (f *flight) lead(mine map[int64]*call, IDs []int64, fn func(context.Context, []int64) (map[int64]interface{}, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), rebuildTimeout)
+	defer cancel()
+
+	var found map[int64]interface{}
+	var err error
+	defer func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		for ID, cl := range mine {
+			cl.value, cl.found = found[ID]
+			cl.err = err
+			delete(f.calls, ID)
+			close(cl.done)
+		}
+	}()
+
+	found, err = fn(ctx, IDs)
+}
+
+const leasePrefix = "lease-"
+
+func leaseKey(key string) string {
+	return leasePrefix + key
+}
+
+// lease takes, for a short while, the leases to rebuild the entries of IDs; it returns
+// the IDs to rebuild, the keys of the leases it holds and the IDs whose lease another
+// server holds. An ID whose lease cannot be taken for a failure is rebuilt.
+func (c *Cache) lease(IDs []int64, key func(ID int64) string) ([]int64, []string, []int64) {
+	errs := make([]error, len(IDs))
+	each(len(IDs), func(i int) {
+		errs[i] = c.client.Add(&memcache.Item{Key: leaseKey(key(IDs[i])), Value: []byte{1}, Expiration: leaseExpiration})
+	})
+
+	rebuild := make([]int64, 0, len(IDs))
+	held := make([]string, 0, len(IDs))
+	var others []int64
+	for i, ID := range IDs {
+		switch errs[i] {
+		case nil:
+			held = append(held, leaseKey(key(ID)))
+		case memcache.ErrNotStored:
+			others = append(others, ID)
+			continue
+		}
+
+		rebuild = append(rebuild, ID)
+	}
+
+	return rebuild, held, others
+}
+
+// release gives back the leases once their entries are rebuilt.
+func (c *Cache) release(held []string) {
+	each(len(held), func(i int) {
+		_ = c.client.Delete(held[i])
+	})
+}
+
+// each calls fn with every index below n, up to leaseConcurrency of them at once.
+func each(n int, fn func(i int)) {
+	if n == 1 {
+		fn(0)
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, leaseConcurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,218 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rafaelsq/boiler/pkg/cache"
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gated counts the FetchUsers calls of a storage and holds them until open is closed
+// or their context is done.
+type gated struct {
+	iface.Storage
+
+	open  chan struct{}
+	calls int64
+}
+
+func (g *gated) FetchUsers(ctx context.Context, includeDeleted bool, IDs ...int64) ([]*entity.User, error) {
+	atomic.AddInt64(&g.calls, 1)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-g.open:
+	}
+	return g.Storage.FetchUsers(ctx, includeDeleted, IDs...)
+}
+
+func newGated(t *testing.T) (*gated, int64) {
+	st := memory.New()
+	tx, err := st.Tx()
+	require.Nil(t, err)
+	userID, err := st.AddUser(context.Background(), tx, "John")
+	require.Nil(t, err)
+	require.Nil(t, tx.Commit())
+
+	return &gated{Storage: st, open: make(chan struct{})}, userID
+}
+
+// fetch calls FetchUsers of each cache concurrently and returns the names each found.
+func fetch(caches []iface.Storage, open func(), IDs ...int64) [][]string {
+	names := make([][]string, len(caches))
+
+	var wg sync.WaitGroup
+	for i, c := range caches {
+		wg.Add(1)
+		go func(i int, c iface.Storage) {
+			defer wg.Done()
+
+			users, err := c.FetchUsers(context.Background(), false, IDs...)
+			if err != nil {
+				names[i] = []string{err.Error()}
+				return
+			}
+			for _, user := range users {
+				names[i] = append(names[i], user.Name)
+			}
+		}(i, c)
+	}
+
+	// the fetches wait for the first one
+	time.Sleep(20 * time.Millisecond)
+	open()
+	wg.Wait()
+
+	return names
+}
+
+func TestFetchUsersCoalesces(t *testing.T) {
+	// concurrent misses in a process fetch once
+	{
+		st, userID := newGated(t)
+		c := cache.New(newClient(), st, cache.DefaultConfig)
+
+		caches := []iface.Storage{c, c, c, c, c}
+		names := fetch(caches, func() { close(st.open) }, userID, userID)
+		for _, n := range names {
+			assert.Equal(t, []string{"John", "John"}, n)
+		}
+		assert.Equal(t, int64(1), atomic.LoadInt64(&st.calls))
+	}
+
+	// concurrent misses in servers sharing memcache fetch once
+	{
+		st, userID := newGated(t)
+		mc := newClient()
+
+		caches := []iface.Storage{cache.New(mc, st, cache.DefaultConfig), cache.New(mc, st, cache.DefaultConfig)}
+		names := fetch(caches, func() { close(st.open) }, userID)
+		for _, n := range names {
+			assert.Equal(t, []string{"John"}, n)
+		}
+		assert.Equal(t, int64(1), atomic.LoadInt64(&st.calls))
+	}
+
+	// a waiter gets the users even if the call it waits for is canceled
+	{
+		st, userID := newGated(t)
+		c := cache.New(newClient(), st, cache.DefaultConfig)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		leader := make(chan error)
+		go func() {
+			_, err := c.FetchUsers(ctx, false, userID)
+			leader <- err
+		}()
+
+		var names []string
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			// waits for the leader, which is fetching already
+			time.Sleep(10 * time.Millisecond)
+			users, err := c.FetchUsers(context.Background(), false, userID)
+			assert.Nil(t, err)
+			for _, user := range users {
+				names = append(names, user.Name)
+			}
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+		assert.Equal(t, context.Canceled, <-leader)
+
+		close(st.open)
+		<-done
+		assert.Equal(t, []string{"John"}, names)
+		assert.Equal(t, int64(1), atomic.LoadInt64(&st.calls))
+	}
+
+	// fetches once the wait for a lease never released is over
+	{
+		st, userID := newGated(t)
+		close(st.open)
+		mc := newClient()
+		mc.items[fmt.Sprintf("lease-user-%d", userID)] = []byte{1}
+
+		users, err := cache.New(mc, st, cache.DefaultConfig).FetchUsers(context.Background(), false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, int64(1), atomic.LoadInt64(&st.calls))
+	}
+}
+
+// counting tracks how many Adds and Deletes run at once.
+type counting struct {
+	*client
+
+	mu       sync.Mutex
+	inflight int
+	most     int
+}
+
+func (c *counting) track(fn func() error) error {
+	c.mu.Lock()
+	c.inflight++
+	if c.inflight > c.most {
+		c.most = c.inflight
+	}
+	c.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+	err := fn()
+
+	c.mu.Lock()
+	c.inflight--
+	c.mu.Unlock()
+
+	return err
+}
+
+func (c *counting) Add(item *memcache.Item) error {
+	return c.track(func() error { return c.client.Add(item) })
+}
+
+func (c *counting) Delete(key string) error {
+	return c.track(func() error { return c.client.Delete(key) })
+}
+
+func TestFetchUsersLeasesAtOnce(t *testing.T) {
+	ctx := context.Background()
+
+	st := memory.New()
+	tx, err := st.Tx()
+	require.Nil(t, err)
+	IDs := make([]int64, 0, 40)
+	for i := 0; i < cap(IDs); i++ {
+		ID, err := st.AddUser(ctx, tx, fmt.Sprintf("User %d", i))
+		require.Nil(t, err)
+		IDs = append(IDs, ID)
+	}
+	require.Nil(t, tx.Commit())
+
+	mc := &counting{client: newClient()}
+	users, err := cache.New(mc, st, cache.DefaultConfig).FetchUsers(ctx, false, IDs...)
+	assert.Nil(t, err)
+	assert.Len(t, users, len(IDs))
+
+	// the leases of a batch are not taken one round trip after the other
+	assert.True(t, mc.most > 1)
+	assert.True(t, mc.most <= 16)
+
+	for _, ID := range IDs {
+		_, has := mc.items[fmt.Sprintf("lease-user-%d", ID)]
+		assert.False(t, has)
+	}
+}
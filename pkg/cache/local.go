@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rafaelsq/boiler/pkg/log"
+	"github.com/rafaelsq/errors"
+)
+
+// generationShards generations, stored at generationPrefix and the shard, count the
+// deletes of every instance; a change drops the local items of that shard only.
+const (
+	generationPrefix = "cache-generation-"
+	generationShards = 64
+)
+
+var generationKeys = func() []string {
+	keys := make([]string, generationShards)
+	for s := range keys {
+		keys[s] = generationPrefix + strconv.Itoa(s)
+	}
+	return keys
+}()
+
+// GenerationClient is the subset of *memcache.Client used by Local.
+type GenerationClient interface {
+	Client
+	Increment(key string, delta uint64) (uint64, error)
+}
+
+// Stats counts the lookups each tier answered and missed.
+type Stats struct {
+	LocalHits      uint64 `json:"local_hits"`
+	LocalMisses    uint64 `json:"local_misses"`
+	MemcacheHits   uint64 `json:"memcache_hits"`
+	MemcacheMisses uint64 `json:"memcache_misses"`
+}
+
+// NewLocal returns a Client keeping up to size items of client in process for ttl;
+// deletes made by any instance reach the others within check.
+func NewLocal(client GenerationClient, size int, ttl, check time.Duration) (*Local, error) {
+	items, err := lru.New(size)
+	if err != nil {
+		return nil, errors.New("could not create local cache").SetArg("size", size).SetParent(err)
+	}
+
+	return &Local{client: client, items: items, ttl: ttl, check: check}, nil
+}
+
+// Local is an in-process LRU in front of memcache. Deletes bump the generation of
+// the key's shard, shared through memcache, which every instance reads at most once
+// per check and, once it changed, drops the items of that shard; until then they may
+// answer for up to check.
+type Local struct {
+	client GenerationClient
+	items  *lru.Cache
+	ttl    time.Duration
+	check  time.Duration
+
+	mu sync.Mutex
+	// shared are the generations last read from memcache; generations, those the
+	// items are kept at, change with them and with every local delete.
+	shared      [generationShards]uint64
+	generations [generationShards]uint64
+	checked     time.Time
+	refreshing  bool
+
+	stats Stats
+}
+
+type localItem struct {
+	value      []byte
+	expires    time.Time
+	generation uint64
+}
+
+// GetMulti answers from process what it can; if memcache fails, it logs and returns
+// those items alone.
+func (l *Local) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	generations := l.refresh()
+	now := time.Now()
+
+	items := make(map[string]*memcache.Item, len(keys))
+	missing := make([]string, 0, len(keys))
+	var hits, misses uint64
+	for _, key := range keys {
+		if shared(key) {
+			missing = append(missing, key)
+			continue
+		}
+
+		if v, has := l.items.Get(key); has {
+			item := v.(*localItem)
+			if item.generation == generations[shard(key)] && now.Before(item.expires) {
+				items[key] = &memcache.Item{Key: key, Value: item.value}
+				hits++
+				continue
+			}
+		}
+
+		missing = append(missing, key)
+		misses++
+	}
+
+	atomic.AddUint64(&l.stats.LocalHits, hits)
+	atomic.AddUint64(&l.stats.LocalMisses, misses)
+
+	if len(missing) == 0 {
+		return items, nil
+	}
+
+	found, err := l.client.GetMulti(missing)
+	if err != nil {
+		log.Log(errors.New("could not read memcache").SetArg("keys", missing).SetParent(err))
+		return items, nil
+	}
+
+	hits = 0
+	for key, item := range found {
+		items[key] = item
+		if !shared(key) {
+			l.add(key, item.Value, generations)
+			hits++
+		}
+	}
+
+	atomic.AddUint64(&l.stats.MemcacheHits, hits)
+	atomic.AddUint64(&l.stats.MemcacheMisses, misses-hits)
+
+	return items, nil
+}
+
+func (l *Local) Set(item *memcache.Item) error {
+	if !shared(item.Key) {
+		l.add(item.Key, item.Value, l.refresh())
+	}
+	return l.client.Set(item)
+}
+
+// Add is not kept in process; the items it adds are shared between servers.
+func (l *Local) Add(item *memcache.Item) error {
+	return l.client.Add(item)
+}
+
+// Delete bumps the generation of the key's shard unless key is never kept in process.
+func (l *Local) Delete(key string) error {
+	if shared(key) {
+		return l.client.Delete(key)
+	}
+
+	err := l.client.Delete(key)
+
+	s := shard(key)
+	l.mu.Lock()
+	// reads in flight since before the delete cannot add the key back
+	l.generations[s]++
+	l.items.Remove(key)
+	l.mu.Unlock()
+
+	l.bump(s)
+
+	return err
+}
+
+// Stats returns the lookups counted so far.
+func (l *Local) Stats() Stats {
+	return Stats{
+		LocalHits:      atomic.LoadUint64(&l.stats.LocalHits),
+		LocalMisses:    atomic.LoadUint64(&l.stats.LocalMisses),
+		MemcacheHits:   atomic.LoadUint64(&l.stats.MemcacheHits),
+		MemcacheMisses: atomic.LoadUint64(&l.stats.MemcacheMisses),
+	}
+}
+
+// add keeps value unless the generation of its shard changed since it was read.
+func (l *Local) add(key string, value []byte, generations [generationShards]uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	generation := generations[shard(key)]
+	if generation != l.generations[shard(key)] {
+		return
+	}
+
+	l.items.Add(key, &localItem{value: value, expires: time.Now().Add(l.ttl), generation: generation})
+}
+
+// bump increments the shared generation of shard s for the other instances.
+func (l *Local) bump(s int) {
+	key := generationKeys[s]
+	generation, err := l.client.Increment(key, 1)
+	if err == memcache.ErrCacheMiss {
+		generation, err = 1, l.client.Add(&memcache.Item{Key: key, Value: []byte("1")})
+		if err == memcache.ErrNotStored {
+			generation, err = l.client.Increment(key, 1)
+		}
+	}
+	if err != nil {
+		log.Log(errors.New("could not bump cache generation").SetArg("shard", s).SetParent(err))
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// the local items were dropped already unless another instance bumped it too
+	if generation == l.shared[s]+1 {
+		l.shared[s] = generation
+	}
+}
+
+// refresh reads the shared generations if they were not read within check, and
+// returns the current ones; the items of a shard are dropped once its generation
+// changed, and every item once they cannot be read. Only one call reads them at a
+// time, without holding mu; the others return the generations they are replacing.
+func (l *Local) refresh() [generationShards]uint64 {
+	l.mu.Lock()
+	if l.refreshing || time.Since(l.checked) < l.check {
+		defer l.mu.Unlock()
+		return l.generations
+	}
+
+	l.refreshing = true
+	l.checked = time.Now()
+	l.mu.Unlock()
+
+	var read [generationShards]uint64
+	items, err := l.client.GetMulti(generationKeys)
+	if err == nil {
+		for s, key := range generationKeys {
+			if item, has := items[key]; has {
+				if read[s], err = strconv.ParseUint(string(item.Value), 10, 64); err != nil {
+					break
+				}
+			}
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refreshing = false
+
+	if err != nil {
+		log.Log(errors.New("could not read cache generations").SetParent(err))
+		l.items.Purge()
+		// the items added before the failure are not used again
+		for s := range l.generations {
+			l.generations[s]++
+		}
+		return l.generations
+	}
+
+	for s := range read {
+		if read[s] != l.shared[s] {
+			l.shared[s] = read[s]
+			l.generations[s]++
+		}
+	}
+
+	return l.generations
+}
+
+// shared reports whether key is read from memcache alone: leases and marks are how
+// servers coordinate, so no copy of them is kept in process.
+func shared(key string) bool {
+	return strings.HasPrefix(key, leasePrefix) || strings.HasPrefix(key, markPrefix)
+}
+
+// shard returns the generation shard of key.
+func shard(key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % generationShards)
+}
@@ -0,0 +1,218 @@
+package cache_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rafaelsq/boiler/pkg/cache"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/rafaelsq/boiler/pkg/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) iface.Storage {
+		local, err := cache.NewLocal(newClient(), 100, time.Minute, 0)
+		require.Nil(t, err)
+
+		return cache.New(local, memory.New(), cache.DefaultConfig)
+	})
+}
+
+func TestLocal(t *testing.T) {
+	mc := newClient()
+	assert.Nil(t, mc.Set(&memcache.Item{Key: "a", Value: []byte("1")}))
+
+	// fails if size is invalid
+	{
+		_, err := cache.NewLocal(mc, 0, time.Minute, time.Minute)
+		assert.NotNil(t, err)
+	}
+
+	// answers from process once read
+	{
+		local, err := cache.NewLocal(mc, 10, time.Minute, time.Minute)
+		require.Nil(t, err)
+
+		items, err := local.GetMulti([]string{"a", "b"})
+		assert.Nil(t, err)
+		assert.Len(t, items, 1)
+
+		items, err = local.GetMulti([]string{"a"})
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("1"), items["a"].Value)
+
+		assert.Equal(t, cache.Stats{LocalHits: 1, LocalMisses: 2, MemcacheHits: 1, MemcacheMisses: 1}, local.Stats())
+	}
+
+	// reads memcache again after ttl
+	{
+		local, err := cache.NewLocal(mc, 10, time.Millisecond, time.Minute)
+		require.Nil(t, err)
+
+		_, err = local.GetMulti([]string{"a"})
+		assert.Nil(t, err)
+		time.Sleep(2 * time.Millisecond)
+		_, err = local.GetMulti([]string{"a"})
+		assert.Nil(t, err)
+
+		assert.Equal(t, uint64(2), local.Stats().MemcacheHits)
+	}
+
+	// deletes on another instance are seen within check
+	{
+		a, err := cache.NewLocal(mc, 10, time.Minute, 10*time.Millisecond)
+		require.Nil(t, err)
+		b, err := cache.NewLocal(mc, 10, time.Minute, 10*time.Millisecond)
+		require.Nil(t, err)
+
+		_, err = b.GetMulti([]string{"a"})
+		assert.Nil(t, err)
+
+		assert.Nil(t, a.Delete("a"))
+
+		items, err := a.GetMulti([]string{"a"})
+		assert.Nil(t, err)
+		assert.Len(t, items, 0)
+
+		time.Sleep(20 * time.Millisecond)
+
+		items, err = b.GetMulti([]string{"a"})
+		assert.Nil(t, err)
+		assert.Len(t, items, 0)
+	}
+
+	// deletes on another instance keep the keys of other shards
+	{
+		assert.Nil(t, mc.Set(&memcache.Item{Key: "b", Value: []byte("2")}))
+
+		a, err := cache.NewLocal(mc, 10, time.Minute, 10*time.Millisecond)
+		require.Nil(t, err)
+		b, err := cache.NewLocal(mc, 10, time.Minute, 10*time.Millisecond)
+		require.Nil(t, err)
+
+		assert.Nil(t, mc.Set(&memcache.Item{Key: "a", Value: []byte("1")}))
+		_, err = b.GetMulti([]string{"a", "b"})
+		assert.Nil(t, err)
+
+		assert.Nil(t, a.Delete("a"))
+		time.Sleep(20 * time.Millisecond)
+
+		items, err := b.GetMulti([]string{"a", "b"})
+		assert.Nil(t, err)
+		assert.Len(t, items, 1)
+		assert.Equal(t, []byte("2"), items["b"].Value)
+		assert.Equal(t, uint64(1), b.Stats().LocalHits)
+	}
+
+	// reads in flight during a delete do not add the key back
+	{
+		assert.Nil(t, mc.Set(&memcache.Item{Key: "a", Value: []byte("1")}))
+
+		hc := &hookClient{client: mc}
+		local, err := cache.NewLocal(hc, 10, time.Minute, time.Minute)
+		require.Nil(t, err)
+
+		hc.after = func() {
+			hc.after = nil
+			assert.Nil(t, local.Delete("a"))
+		}
+
+		items, err := local.GetMulti([]string{"a"})
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("1"), items["a"].Value)
+
+		items, err = local.GetMulti([]string{"a"})
+		assert.Nil(t, err)
+		assert.Len(t, items, 0)
+	}
+
+	// answers from process while the generations are read
+	{
+		sc := &slowClient{client: mc}
+		local, err := cache.NewLocal(sc, 10, time.Minute, 10*time.Millisecond)
+		require.Nil(t, err)
+
+		assert.Nil(t, local.Set(&memcache.Item{Key: "c", Value: []byte("3")}))
+		time.Sleep(20 * time.Millisecond)
+
+		sc.block, sc.blocked = make(chan struct{}), make(chan struct{})
+		refreshed := make(chan struct{})
+		go func() {
+			defer close(refreshed)
+			_, _ = local.GetMulti([]string{"c"})
+		}()
+		<-sc.blocked
+
+		items, err := local.GetMulti([]string{"c"})
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("3"), items["c"].Value)
+
+		close(sc.block)
+		<-refreshed
+	}
+
+	// keeps the local hits if memcache fails
+	{
+		local, err := cache.NewLocal(mc, 10, time.Minute, time.Minute)
+		require.Nil(t, err)
+
+		assert.Nil(t, local.Set(&memcache.Item{Key: "c", Value: []byte("3")}))
+
+		mc.err = memcache.ErrServerError
+		items, err := local.GetMulti([]string{"c", "d"})
+		assert.Nil(t, err)
+		assert.Len(t, items, 1)
+		assert.Equal(t, []byte("3"), items["c"].Value)
+		mc.err = nil
+	}
+
+	// drops every item if the generations cannot be read
+	{
+		local, err := cache.NewLocal(mc, 10, time.Minute, 0)
+		require.Nil(t, err)
+
+		assert.Nil(t, local.Set(&memcache.Item{Key: "c", Value: []byte("3")}))
+
+		mc.err = memcache.ErrServerError
+		items, err := local.GetMulti([]string{"c"})
+		assert.Nil(t, err)
+		assert.Len(t, items, 0)
+		mc.err = nil
+	}
+}
+
+// hookClient calls after once its GetMulti read memcache.
+type hookClient struct {
+	*client
+	after func()
+}
+
+func (c *hookClient) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	items, err := c.client.GetMulti(keys)
+	if c.after != nil && len(keys) == 1 && keys[0] == "a" {
+		c.after()
+	}
+
+	return items, err
+}
+
+// slowClient holds the reads of the generations until block is closed, once blocked is.
+type slowClient struct {
+	*client
+	block   chan struct{}
+	blocked chan struct{}
+}
+
+func (c *slowClient) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	if c.block != nil && strings.HasPrefix(keys[0], "cache-generation-") {
+		close(c.blocked)
+		<-c.block
+	}
+
+	return c.client.GetMulti(keys)
+}
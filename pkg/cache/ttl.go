@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rafaelsq/boiler/pkg/log"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// refreshTimeout bounds a background refresh.
+const refreshTimeout = 10 * time.Second
+
+// set caches payload under key for ttl and then, served as stale, for stale; a zero
+// ttl keeps it until it is invalidated or evicted. The value is a msgp array of
+// when it goes stale, in Unix nanoseconds or 0 for never, and payload.
+func (c *Cache) set(key string, payload []byte, ttl, stale time.Duration) {
+	var until int64
+	var exp int32
+	if ttl > 0 {
+		exp = expiration(ttl + stale)
+		if stale > 0 {
+			until = time.Now().Add(ttl).UnixNano()
+		}
+	}
+
+	value := msgp.AppendArrayHeader(make([]byte, 0, len(payload)+16), 2)
+	value = msgp.AppendInt64(value, until)
+	value = append(value, payload...)
+
+	if err := c.client.Set(&memcache.Item{Key: key, Value: value, Expiration: exp}); err != nil {
+		log.Log(err)
+	}
+}
+
+// open returns the payload of a value set caches and whether it is stale.
+func open(value []byte) ([]byte, bool, error) {
+	n, b, err := msgp.ReadArrayHeaderBytes(value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if n != 2 {
+		return nil, false, msgp.ArrayError{Wanted: 2, Got: n}
+	}
+
+	until, payload, err := msgp.ReadInt64Bytes(b)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return payload, until != 0 && time.Now().UnixNano() >= until, nil
+}
+
+// refresh calls fn in the background with those of IDs whose entries, named by key,
+// are not being refreshed already, unless every worker is busy.
+func (c *Cache) refresh(IDs []int64, key func(ID int64) string, fn func(ctx context.Context, IDs []int64)) {
+	c.mu.Lock()
+	keys := make([]string, 0, len(IDs))
+	pending := make([]int64, 0, len(IDs))
+	for _, ID := range IDs {
+		if k := key(ID); !c.refreshing[k] {
+			c.refreshing[k] = true
+			keys = append(keys, k)
+			pending = append(pending, ID)
+		}
+	}
+
+	if len(pending) == 0 {
+		c.mu.Unlock()
+		return
+	}
+
+	select {
+	case c.workers <- struct{}{}:
+	default:
+		for _, k := range keys {
+			delete(c.refreshing, k)
+		}
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			for _, k := range keys {
+				delete(c.refreshing, k)
+			}
+			c.mu.Unlock()
+
+			<-c.workers
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+
+		fn(ctx, pending)
+	}()
+}
@@ -0,0 +1,124 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rafaelsq/boiler/pkg/cache"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventually reports whether cond holds within a second.
+func eventually(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestTTL(t *testing.T) {
+	ctx := context.Background()
+
+	st := memory.New()
+	tx, err := st.Tx()
+	require.Nil(t, err)
+	userID, err := st.AddUser(ctx, tx, "John")
+	require.Nil(t, err)
+	_, err = st.AddEmail(ctx, tx, userID, "john@example.com")
+	require.Nil(t, err)
+	require.Nil(t, tx.Commit())
+
+	userKey := fmt.Sprintf("user-%d", userID)
+	emailsKey := fmt.Sprintf("user-emails-%d", userID)
+
+	// memcache keeps entries for their TTL and the stale time
+	{
+		mc := newClient()
+		c := cache.New(mc, st, cache.Config{UserTTL: 90 * time.Second, EmailTTL: 2 * time.Minute, Stale: 30 * time.Second})
+
+		_, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		_, _, err = c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+
+		assert.Equal(t, int32(120), mc.expirations[userKey])
+		assert.Equal(t, int32(150), mc.expirations[emailsKey])
+	}
+
+	// serves stale entries while they are refreshed
+	{
+		mc := newClient()
+		c := cache.New(mc, st, cache.Config{UserTTL: 10 * time.Millisecond, EmailTTL: 10 * time.Millisecond, Stale: time.Minute})
+
+		_, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		_, _, err = c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+
+		// written behind the cache's back
+		tx, err := st.Tx()
+		require.Nil(t, err)
+		require.Nil(t, st.UpdateUser(ctx, tx, userID, 1, "Jane"))
+		_, err = st.AddEmail(ctx, tx, userID, "jane@example.com")
+		require.Nil(t, err)
+		require.Nil(t, tx.Commit())
+
+		time.Sleep(20 * time.Millisecond)
+
+		users, err := c.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "John", users[0].Name)
+
+		emails, _, err := c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 1)
+
+		assert.True(t, eventually(func() bool {
+			users, err := c.FetchUsers(ctx, false, userID)
+			return err == nil && len(users) == 1 && users[0].Name == "Jane"
+		}))
+		assert.True(t, eventually(func() bool {
+			emails, _, err := c.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+			return err == nil && len(emails) == 2
+		}))
+	}
+
+	// skips refreshes while every worker is busy
+	{
+		mc := newClient()
+		config := cache.Config{UserTTL: 10 * time.Millisecond, Stale: time.Minute, Refreshes: 1}
+
+		tx, err := st.Tx()
+		require.Nil(t, err)
+		otherID, err := st.AddUser(ctx, tx, "Jim")
+		require.Nil(t, err)
+		require.Nil(t, tx.Commit())
+
+		_, err = cache.New(mc, st, config).FetchUsers(ctx, false, userID, otherID)
+		assert.Nil(t, err)
+		time.Sleep(20 * time.Millisecond)
+
+		g := &gated{Storage: st, open: make(chan struct{})}
+		c := cache.New(mc, g, config)
+
+		for _, ID := range []int64{userID, otherID, otherID} {
+			users, err := c.FetchUsers(ctx, false, ID)
+			assert.Nil(t, err)
+			assert.Len(t, users, 1)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int64(1), atomic.LoadInt64(&g.calls))
+		close(g.open)
+	}
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rafaelsq/boiler/pkg/iface"
+)
+
+const (
+	markPrefix = "mark-"
+
+	// markExpiration is how long, in seconds, the mark of an invalidation is kept;
+	// longer than a fetch may take, bounded by rebuildTimeout and refreshTimeout
+	markExpiration = 60
+)
+
+func markKey(key string) string {
+	return markPrefix + key
+}
+
+// expire marks key as invalidated and deletes it; see guard.
+func expire(client Client, key string) {
+	mark := strconv.FormatInt(time.Now().UnixNano(), 36)
+	_ = client.Set(&memcache.Item{Key: markKey(key), Value: []byte(mark), Expiration: markExpiration})
+	_ = client.Delete(key)
+}
+
+// Tx wraps a storage transaction and holds the cache keys its writes invalidate;
+// they are expired once the transaction commits and dropped if it rolls back.
+type Tx struct {
+	iface.Tx
+
+	client Client
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (tx *Tx) Commit() error {
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+
+	tx.mu.Lock()
+	keys := tx.keys
+	tx.keys = nil
+	tx.mu.Unlock()
+
+	for _, key := range keys {
+		expire(tx.client, key)
+	}
+
+	return nil
+}
+
+func (tx *Tx) Rollback() error {
+	tx.mu.Lock()
+	tx.keys = nil
+	tx.mu.Unlock()
+
+	return tx.Tx.Rollback()
+}
+
+// unwrap returns the storage transaction behind tx.
+func unwrap(tx iface.Tx) iface.Tx {
+	if t, ok := tx.(*Tx); ok {
+		return t.Tx
+	}
+
+	return tx
+}
+
+// invalidate expires keys when tx commits; without a cache Tx it expires them right away.
+func (c *Cache) invalidate(tx iface.Tx, keys ...string) {
+	t, ok := tx.(*Tx)
+	if !ok {
+		for _, key := range keys {
+			expire(c.client, key)
+		}
+		return
+	}
+
+	t.mu.Lock()
+	t.keys = append(t.keys, keys...)
+	t.mu.Unlock()
+}
+
+// guard reads the marks of keys before the rows their entries cache are read from
+// storage. The func it returns, called once the entries are set, deletes those whose
+// mark changed meanwhile: they may hold rows read before a commit that expired them
+// first. A commit marking them after the second read deletes them itself.
+func (c *Cache) guard(keys []string) func() {
+	before, err := c.marks(keys)
+
+	return func() {
+		after, aerr := c.marks(keys)
+		for _, key := range keys {
+			if err != nil || aerr != nil || before[key] != after[key] {
+				_ = c.client.Delete(key)
+			}
+		}
+	}
+}
+
+// marks returns the marks of keys, by key; keys never expired have none.
+func (c *Cache) marks(keys []string) (map[string]string, error) {
+	mkeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		mkeys = append(mkeys, markKey(key))
+	}
+
+	items, err := c.client.GetMulti(mkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	marks := make(map[string]string, len(items))
+	for i, key := range keys {
+		if item, has := items[mkeys[i]]; has {
+			marks[key] = string(item.Value)
+		}
+	}
+
+	return marks, nil
+}
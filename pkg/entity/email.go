@@ -8,4 +8,6 @@ type Email struct {
 	UserID  int64     `json:"user_id"`
 	Address string    `json:"address"`
 	Created time.Time `json:"created"`
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
@@ -8,4 +8,7 @@ type User struct {
 	Name    string    `json:"name"`
 	Created time.Time `json:"created"`
 	Updated time.Time `json:"updated"`
+	Version int64     `json:"version"`
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,80 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rafaelsq/boiler/pkg/log"
+	"github.com/rafaelsq/errors"
+)
+
+// Kind names a change the service committed.
+type Kind string
+
+const (
+	UserAdded    Kind = "userAdded"
+	UserDeleted  Kind = "userDeleted"
+	EmailAdded   Kind = "emailAdded"
+	EmailDeleted Kind = "emailDeleted"
+)
+
+// buffer is how many events a subscriber may fall behind before it misses them.
+const buffer = 64
+
+// Event is a committed change; EmailID is 0 for user changes and UserID is 0 for EmailDeleted.
+type Event struct {
+	Kind    Kind
+	UserID  int64
+	EmailID int64
+}
+
+func New() *Bus {
+	return &Bus{
+		subs: make(map[chan Event]Kind),
+	}
+}
+
+// Bus hands the events published in this process to their subscribers.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan Event]Kind
+}
+
+// Publish delivers e to the subscribers of its kind without blocking; a subscriber
+// whose buffer is full misses it.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch, kind := range b.subs {
+		if kind != e.Kind {
+			continue
+		}
+
+		select {
+		case ch <- e:
+		default:
+			log.Log(errors.New("subscriber missed event").SetArg("kind", e.Kind))
+		}
+	}
+}
+
+// Subscribe returns the events of kind published until ctx is done, when the channel is closed.
+func (b *Bus) Subscribe(ctx context.Context, kind Kind) <-chan Event {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	b.subs[ch] = kind
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+
+	return ch
+}
@@ -0,0 +1,57 @@
+package event_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	// delivers events of the subscribed kind
+	{
+		b := event.New()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		added := b.Subscribe(ctx, event.UserAdded)
+		deleted := b.Subscribe(ctx, event.UserDeleted)
+
+		b.Publish(event.Event{Kind: event.UserAdded, UserID: 1})
+		b.Publish(event.Event{Kind: event.EmailAdded, UserID: 1, EmailID: 2})
+
+		assert.Equal(t, event.Event{Kind: event.UserAdded, UserID: 1}, <-added)
+		assert.Len(t, added, 0)
+		assert.Len(t, deleted, 0)
+	}
+
+	// closes the channel when ctx is done
+	{
+		b := event.New()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		added := b.Subscribe(ctx, event.UserAdded)
+		cancel()
+
+		_, open := <-added
+		assert.False(t, open)
+
+		b.Publish(event.Event{Kind: event.UserAdded, UserID: 1})
+	}
+
+	// a subscriber that falls behind misses events instead of blocking
+	{
+		b := event.New()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		added := b.Subscribe(ctx, event.UserAdded)
+		for i := 0; i < 65; i++ {
+			b.Publish(event.Event{Kind: event.UserAdded, UserID: int64(i)})
+		}
+
+		assert.Equal(t, 64, len(added))
+		assert.Equal(t, int64(0), (<-added).UserID)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"log"
 	"net/http"
 	"runtime/debug"
+	"strings"
 
 	"github.com/99designs/gqlgen/handler"
 	graphql "github.com/rafaelsq/boiler/pkg/graphql/internal"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/loader"
 	"github.com/rafaelsq/boiler/pkg/iface"
 )
 
@@ -16,15 +18,45 @@ func PlayHandle() http.HandlerFunc {
 	return handler.Playground("Users", "/graphql/query")
 }
 
-func QueryHandleFunc(service iface.Service) http.HandlerFunc {
-	return handler.GraphQL(
-		graphql.NewExecutableSchema(graphql.Config{
-			Resolvers: graphql.NewResolver(service),
-		}),
+// Limits bounds the depth and complexity of the operations QueryHandleFunc runs.
+type Limits = graphql.Limits
+
+// DefaultLimits admits two levels of default pages, such as users with their emails.
+var DefaultLimits = Limits{MaxDepth: 12, MaxComplexity: 20000}
+
+// QueryHandleFunc serves GraphQL queries, and subscriptions over websocket, within limits;
+// the lookups of each HTTP request are batched by its loaders. Documents sent by hash
+// are looked up in queries, which a nil disables; an *AllowList also refuses any other.
+func QueryHandleFunc(service iface.Service, limits Limits, queries QueryCache) http.HandlerFunc {
+	es := graphql.NewLimitedSchema(graphql.NewExecutableSchema(graphql.Config{
+		Resolvers:  graphql.NewResolver(service),
+		Complexity: graphql.NewComplexity(),
+	}), limits)
+	if allowList, ok := queries.(*AllowList); ok {
+		es = graphql.NewAllowListSchema(es, allowList.Allows)
+	}
+
+	options := []handler.Option{
 		handler.RecoverFunc(func(ctx context.Context, err interface{}) error {
 			log.Print(err)
 			debug.PrintStack()
 			return errors.New("internal server error")
 		}),
-	)
+	}
+	if queries != nil {
+		options = append(options, handler.EnablePersistedQueryCache(queries))
+	}
+
+	h := handler.GraphQL(es, options...)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// a websocket outlives its request, so its loaders would keep stale results
+		if strings.Contains(r.Header.Get("Upgrade"), "websocket") {
+			h(w, r)
+			return
+		}
+
+		ctx := loader.With(r.Context(), loader.New(r.Context(), service))
+		h(w, r.WithContext(ctx))
+	}
 }
@@ -0,0 +1,284 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rafaelsq/boiler/pkg/graphql"
+	"github.com/rafaelsq/boiler/pkg/service"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type response struct {
+	Data   map[string]interface{}
+	Errors []struct {
+		Message    string
+		Extensions map[string]interface{}
+	}
+}
+
+func query(t *testing.T, h http.HandlerFunc, q string) response {
+	return post(t, h, map[string]interface{}{"query": q})
+}
+
+// persisted sends q by its hash, and in full too unless q is empty.
+func persisted(t *testing.T, h http.HandlerFunc, q, hash string) response {
+	params := map[string]interface{}{
+		"extensions": map[string]interface{}{
+			"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": hash},
+		},
+	}
+	if len(q) != 0 {
+		params["query"] = q
+	}
+
+	return post(t, h, params)
+}
+
+func post(t *testing.T, h http.HandlerFunc, params map[string]interface{}) response {
+	body, err := json.Marshal(params)
+	require.Nil(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql/query", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h(w, r)
+
+	var resp response
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp
+}
+
+func TestQueryHandleFuncLimits(t *testing.T) {
+	srv := service.New(memory.New())
+	userID, err := srv.AddUser(context.Background(), "John Doe")
+	require.Nil(t, err)
+	_, err = srv.AddEmail(context.Background(), userID, "john@example.com")
+	require.Nil(t, err)
+
+	h := graphql.QueryHandleFunc(srv, graphql.DefaultLimits, nil)
+
+	// succeed with a page of users and their emails
+	{
+		resp := query(t, h, `{ users { nodes { name emails { nodes { address } } } } }`)
+		assert.Len(t, resp.Errors, 0)
+		assert.NotNil(t, resp.Data["users"])
+	}
+
+	// introspection is not limited by depth
+	{
+		resp := query(t, h, `{ __schema { types { fields { type { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { name } } } } } } } } } } } } }`)
+		assert.Len(t, resp.Errors, 0)
+	}
+
+	// fails if too deep
+	{
+		resp := query(t, h, `{ users(first: 1) { nodes { emails(first: 1) { nodes { user { emails(first: 1) { nodes { user { emails(first: 1) { nodes { user { emails(first: 1) { nodes { id } } } } } } } } } } } } } }`)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "qdepth", resp.Errors[0].Extensions["code"])
+		assert.Equal(t, float64(14), resp.Errors[0].Extensions["depth"])
+		assert.Equal(t, float64(12), resp.Errors[0].Extensions["limit"])
+		assert.Nil(t, resp.Data)
+	}
+
+	// fails if the pages multiply past the complexity limit
+	{
+		resp := query(t, h, `{ users { nodes { emails { nodes { user { emails { nodes { id } } } } } } } }`)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "qcomplex", resp.Errors[0].Extensions["code"])
+		assert.Nil(t, resp.Data)
+	}
+
+	// smaller pages fit
+	{
+		resp := query(t, h, `{ users(first: 5) { nodes { emails(first: 5) { nodes { user { emails(first: 5) { nodes { id } } } } } } } }`)
+		assert.Len(t, resp.Errors, 0)
+	}
+
+	// limits apply to mutations too
+	{
+		h := graphql.QueryHandleFunc(srv, graphql.Limits{MaxDepth: 1}, nil)
+
+		resp := query(t, h, `mutation { addUser(input: {name: "Jane"}) { user { id } } }`)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "qdepth", resp.Errors[0].Extensions["code"])
+	}
+}
+
+func TestQueryHandleFuncPersistedQueries(t *testing.T) {
+	queries, err := graphql.NewLRUQueries(10)
+	require.Nil(t, err)
+
+	h := graphql.QueryHandleFunc(service.New(memory.New()), graphql.DefaultLimits, queries)
+
+	q := `{ users { nodes { id } } }`
+
+	// fails if the hash was not registered
+	{
+		resp := persisted(t, h, "", graphql.QueryHash(q))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "PersistedQueryNotFound", resp.Errors[0].Message)
+	}
+
+	// fails if the hash does not match the query
+	{
+		resp := persisted(t, h, q, graphql.QueryHash("{ users { nodes { name } } }"))
+		require.Len(t, resp.Errors, 1)
+		assert.Nil(t, resp.Data)
+	}
+
+	// succeed registering the query
+	{
+		resp := persisted(t, h, q, graphql.QueryHash(q))
+		assert.Len(t, resp.Errors, 0)
+		assert.NotNil(t, resp.Data["users"])
+	}
+
+	// succeed by hash once registered
+	{
+		resp := persisted(t, h, "", graphql.QueryHash(q))
+		assert.Len(t, resp.Errors, 0)
+		assert.NotNil(t, resp.Data["users"])
+	}
+}
+
+func TestQueryHandleFuncAllowList(t *testing.T) {
+	q := `{ users { nodes { id } } }`
+	h := graphql.QueryHandleFunc(service.New(memory.New()), graphql.DefaultLimits, graphql.NewAllowList(q))
+
+	// succeed by hash
+	{
+		resp := persisted(t, h, "", graphql.QueryHash(q))
+		assert.Len(t, resp.Errors, 0)
+		assert.NotNil(t, resp.Data["users"])
+	}
+
+	// succeed in full
+	{
+		resp := query(t, h, q)
+		assert.Len(t, resp.Errors, 0)
+		assert.NotNil(t, resp.Data["users"])
+	}
+
+	// fails if not registered
+	{
+		resp := query(t, h, `{ users { nodes { name } } }`)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "qnallow", resp.Errors[0].Extensions["code"])
+		assert.Nil(t, resp.Data)
+	}
+
+	// fails registering by hash
+	{
+		other := `{ users { nodes { name } } }`
+		resp := persisted(t, h, other, graphql.QueryHash(other))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "qnallow", resp.Errors[0].Extensions["code"])
+
+		resp = persisted(t, h, "", graphql.QueryHash(other))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "PersistedQueryNotFound", resp.Errors[0].Message)
+	}
+}
+
+func TestQueryHandleFuncNode(t *testing.T) {
+	srv := service.New(memory.New())
+	_, err := srv.AddUser(context.Background(), "John Doe")
+	require.Nil(t, err)
+
+	h := graphql.QueryHandleFunc(srv, graphql.DefaultLimits, nil)
+
+	users := query(t, h, `{ users { nodes { id emails { nodes { id } } } } }`)
+	require.Len(t, users.Errors, 0)
+	userID := users.Data["users"].(map[string]interface{})["nodes"].([]interface{})[0].(map[string]interface{})["id"].(string)
+	assert.NotEqual(t, "1", userID)
+
+	// succeed
+	{
+		resp := query(t, h, `{ node(id: "`+userID+`") { __typename id ... on User { name createdAt } } }`)
+		require.Len(t, resp.Errors, 0)
+		node := resp.Data["node"].(map[string]interface{})
+		assert.Equal(t, "User", node["__typename"])
+		assert.Equal(t, userID, node["id"])
+		assert.Equal(t, "John Doe", node["name"])
+
+		createdAt, err := time.Parse(time.RFC3339, node["createdAt"].(string))
+		assert.Nil(t, err)
+		assert.WithinDuration(t, time.Now(), createdAt, time.Minute)
+	}
+
+	// null if the node does not exist
+	{
+		resp := query(t, h, `{ node(id: "VXNlcjo5OQ") { id } }`)
+		assert.Len(t, resp.Errors, 0)
+		assert.Nil(t, resp.Data["node"])
+	}
+
+	// fails if the ID is invalid
+	{
+		resp := query(t, h, `{ node(id: "1") { id } }`)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "invalid ID", resp.Errors[0].Message)
+	}
+}
+
+func TestQueryHandleFuncUsers(t *testing.T) {
+	srv := service.New(memory.New())
+	_, err := srv.AddUser(context.Background(), "John Doe")
+	require.Nil(t, err)
+
+	h := graphql.QueryHandleFunc(srv, graphql.DefaultLimits, nil)
+
+	// succeed with an explicit null includeDeleted
+	{
+		resp := query(t, h, `{ users(includeDeleted: null) { nodes { name } } }`)
+		require.Len(t, resp.Errors, 0)
+		nodes := resp.Data["users"].(map[string]interface{})["nodes"].([]interface{})
+		require.Len(t, nodes, 1)
+		assert.Equal(t, "John Doe", nodes[0].(map[string]interface{})["name"])
+	}
+
+	// succeed with the deprecated limit as first
+	{
+		_, err := srv.AddUser(context.Background(), "Jane Doe")
+		require.Nil(t, err)
+
+		resp := query(t, h, `{ users(limit: 1) { nodes { name } pageInfo { hasNextPage } } }`)
+		require.Len(t, resp.Errors, 0)
+		users := resp.Data["users"].(map[string]interface{})
+		assert.Len(t, users["nodes"].([]interface{}), 1)
+		assert.Equal(t, true, users["pageInfo"].(map[string]interface{})["hasNextPage"])
+	}
+
+	// succeed with no users for first 0, still counting them all
+	{
+		resp := query(t, h, `{ users(first: 0) { nodes { name } totalCount } }`)
+		require.Len(t, resp.Errors, 0)
+		users := resp.Data["users"].(map[string]interface{})
+		assert.Len(t, users["nodes"].([]interface{}), 0)
+		assert.Equal(t, float64(2), users["totalCount"])
+	}
+
+	// succeed filtering by the days created
+	{
+		today := time.Now().UTC().Format("2006-01-02")
+		resp := query(t, h, `{ users(filter: {createdFrom: "`+today+`", createdTo: "`+today+`"}) { nodes { name } } }`)
+		require.Len(t, resp.Errors, 0)
+		nodes := resp.Data["users"].(map[string]interface{})["nodes"].([]interface{})
+		assert.Len(t, nodes, 2)
+	}
+
+	// fails if a date is not YYYY-MM-DD
+	{
+		resp := query(t, h, `{ users(filter: {createdFrom: "07/01/2019"}) { nodes { name } } }`)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "invalid date", resp.Errors[0].Message)
+	}
+}
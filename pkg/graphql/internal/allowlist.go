@@ -0,0 +1,22 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rafaelsq/errors"
+	"github.com/vektah/gqlparser/ast"
+)
+
+var ErrNotAllowed = errors.New("operation is not allowed").SetArg("code", "qnallow")
+
+// NewAllowListSchema returns es, refusing the operations whose document allowed rejects.
+func NewAllowListSchema(es graphql.ExecutableSchema, allowed func(query string) bool) graphql.ExecutableSchema {
+	return &guardedSchema{es, func(ctx context.Context, op *ast.OperationDefinition) *graphql.Response {
+		if reqCtx := graphql.GetRequestContext(ctx); reqCtx == nil || !allowed(reqCtx.RawQuery) {
+			return rejection(ErrNotAllowed, map[string]interface{}{})
+		}
+
+		return nil
+	}}
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "github.com/rafaelsq/boiler/pkg/iface"
+
+// UserConnection is a page of users; Filter is kept to count all of them on demand.
+type UserConnection struct {
+	Edges    []*UserEdge `json:"edges"`
+	Nodes    []*User     `json:"nodes"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+
+	Filter iface.FilterUsers `json:"-"`
+}
+
+// EmailConnection is a page of emails; Filter is kept to count all of them on demand.
+type EmailConnection struct {
+	Edges    []*EmailEdge `json:"edges"`
+	Nodes    []*Email     `json:"nodes"`
+	PageInfo *PageInfo    `json:"pageInfo"`
+
+	Filter iface.FilterEmails `json:"-"`
+}
@@ -2,24 +2,92 @@
 
 package entity
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type Node interface {
+	IsNode()
+}
+
+type DeleteEmailResponse struct {
+	DeletedEmailID *string      `json:"deletedEmailID"`
+	UserErrors     []*UserError `json:"userErrors"`
+}
+
+type DeleteUserResponse struct {
+	DeletedUserID *string      `json:"deletedUserID"`
+	UserErrors    []*UserError `json:"userErrors"`
+}
+
 type Email struct {
-	ID      string `json:"id"`
-	Address string `json:"address"`
-	User    *User  `json:"user"`
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"createdAt"`
+	User      *User     `json:"user"`
+}
+
+func (Email) IsNode() {}
+
+type EmailEdge struct {
+	Node   *Email `json:"node"`
+	Cursor string `json:"cursor"`
 }
 
 type EmailResponse struct {
-	Email *Email `json:"email"`
+	Email      *Email       `json:"email"`
+	UserErrors []*UserError `json:"userErrors"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
 }
 
 type User struct {
-	ID     string   `json:"id"`
-	Name   string   `json:"name"`
-	Emails []*Email `json:"emails"`
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Version   int              `json:"version"`
+	Deleted   bool             `json:"deleted"`
+	CreatedAt time.Time        `json:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+	Emails    *EmailConnection `json:"emails"`
+}
+
+func (User) IsNode() {}
+
+type UserEdge struct {
+	Node   *User  `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+type UserError struct {
+	Field   []string `json:"field"`
+	Message string   `json:"message"`
+	Code    string   `json:"code"`
+}
+
+type UserFilter struct {
+	Email       *string    `json:"email"`
+	EmailDomain *string    `json:"emailDomain"`
+	NamePrefix  *string    `json:"namePrefix"`
+	CreatedFrom *time.Time `json:"createdFrom"`
+	CreatedTo   *time.Time `json:"createdTo"`
+}
+
+type UserOrder struct {
+	Field     UserOrderField  `json:"field"`
+	Direction *OrderDirection `json:"direction"`
 }
 
 type UserResponse struct {
-	User *User `json:"user"`
+	User       *User        `json:"user"`
+	UserErrors []*UserError `json:"userErrors"`
 }
 
 type AddEmailInput struct {
@@ -30,3 +98,107 @@ type AddEmailInput struct {
 type AddUserInput struct {
 	Name string `json:"name"`
 }
+
+type DeleteEmailInput struct {
+	EmailID string `json:"emailID"`
+}
+
+type DeleteUserInput struct {
+	UserID          string `json:"userID"`
+	ExpectedVersion int    `json:"expectedVersion"`
+}
+
+type RestoreUserInput struct {
+	UserID          string `json:"userID"`
+	ExpectedVersion int    `json:"expectedVersion"`
+}
+
+type UpdateUserInput struct {
+	UserID          string `json:"userID"`
+	Name            string `json:"name"`
+	ExpectedVersion int    `json:"expectedVersion"`
+}
+
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type UserOrderField string
+
+const (
+	UserOrderFieldID      UserOrderField = "ID"
+	UserOrderFieldName    UserOrderField = "NAME"
+	UserOrderFieldCreated UserOrderField = "CREATED"
+)
+
+var AllUserOrderField = []UserOrderField{
+	UserOrderFieldID,
+	UserOrderFieldName,
+	UserOrderFieldCreated,
+}
+
+func (e UserOrderField) IsValid() bool {
+	switch e {
+	case UserOrderFieldID, UserOrderFieldName, UserOrderFieldCreated:
+		return true
+	}
+	return false
+}
+
+func (e UserOrderField) String() string {
+	return string(e)
+}
+
+func (e *UserOrderField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserOrderField", str)
+	}
+	return nil
+}
+
+func (e UserOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
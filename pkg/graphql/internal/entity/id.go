@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+)
+
+// The typenames that prefix the IDs of their nodes.
+const (
+	UserTypename  = "User"
+	EmailTypename = "Email"
+)
+
+// NewID returns the opaque ID of the typename node stored under ID.
+func NewID(typename string, ID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(typename + ":" + strconv.FormatInt(ID, 10)))
+}
+
+// ParseID returns the typename and storage ID of an ID made by NewID.
+func ParseID(ID string) (string, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(ID)
+	if err != nil {
+		return "", 0, iface.ErrInvalidID
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return "", 0, iface.ErrInvalidID
+	}
+
+	storageID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || storageID < 1 {
+		return "", 0, iface.ErrInvalidID
+	}
+
+	return parts[0], storageID, nil
+}
+
+// UserID returns the storage ID of a User ID; the ID of any other node is invalid.
+func UserID(ID string) (int64, error) {
+	return typedID(UserTypename, ID)
+}
+
+// EmailID returns the storage ID of an Email ID; the ID of any other node is invalid.
+func EmailID(ID string) (int64, error) {
+	return typedID(EmailTypename, ID)
+}
+
+func typedID(typename, ID string) (int64, error) {
+	t, storageID, err := ParseID(ID)
+	if err != nil || t != typename {
+		return 0, iface.ErrInvalidID
+	}
+
+	return storageID, nil
+}
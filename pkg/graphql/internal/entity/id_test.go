@@ -0,0 +1,45 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestID(t *testing.T) {
+	// succeed
+	{
+		ID := entity.NewID(entity.UserTypename, 42)
+		assert.NotContains(t, ID, "42")
+
+		typename, userID, err := entity.ParseID(ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.UserTypename, typename)
+		assert.Equal(t, int64(42), userID)
+
+		userID, err = entity.UserID(ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(42), userID)
+	}
+
+	// user and email IDs differ
+	{
+		assert.NotEqual(t, entity.NewID(entity.UserTypename, 1), entity.NewID(entity.EmailTypename, 1))
+	}
+
+	// fails if of another type
+	{
+		_, err := entity.EmailID(entity.NewID(entity.UserTypename, 42))
+		assert.Equal(t, iface.ErrInvalidID, err)
+	}
+
+	// fails if malformed
+	{
+		for _, ID := range []string{"", "42", "!", entity.NewID("", 1), entity.NewID(entity.UserTypename, 0)} {
+			_, _, err := entity.ParseID(ID)
+			assert.Equal(t, iface.ErrInvalidID, err, ID)
+		}
+	}
+}
@@ -1,22 +1,37 @@
 package entity
 
 import (
-	"strconv"
-
 	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
 )
 
 func NewUser(u *entity.User) *User {
 	return &User{
-		ID:   strconv.FormatInt(u.ID, 10),
-		Name: u.Name,
+		ID:        NewID(UserTypename, u.ID),
+		Name:      u.Name,
+		Version:   int(u.Version),
+		Deleted:   u.DeletedAt != nil,
+		CreatedAt: u.Created,
+		UpdatedAt: u.Updated,
 	}
 }
 
 func NewEmail(e *entity.Email) *Email {
 	return &Email{
-		ID:      strconv.FormatInt(e.ID, 10),
-		Address: e.Address,
-		User:    &User{ID: strconv.FormatInt(e.UserID, 10)},
+		ID:        NewID(EmailTypename, e.ID),
+		Address:   e.Address,
+		CreatedAt: e.Created,
+		User:      &User{ID: NewID(UserTypename, e.UserID)},
 	}
 }
+
+// NewPageInfo describes the page from startID to endID, both 0 if it is empty.
+func NewPageInfo(startID, endID int64, hasPrevious, hasNext bool) *PageInfo {
+	info := &PageInfo{HasPreviousPage: hasPrevious, HasNextPage: hasNext}
+	if startID != 0 {
+		start, end := iface.EncodeCursor(startID), iface.EncodeCursor(endID)
+		info.StartCursor, info.EndCursor = &start, &end
+	}
+
+	return info
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rafaelsq/errors"
+)
+
+// ErrInvalidTime is returned for a Time not given as an RFC3339 string.
+var ErrInvalidTime = errors.New("invalid time").SetArg("code", "itime")
+
+// ErrInvalidDate is returned for a Date not given as a YYYY-MM-DD string.
+var ErrInvalidDate = errors.New("invalid date").SetArg("code", "idate")
+
+const dateLayout = "2006-01-02"
+
+// MarshalTime writes t as RFC3339 in UTC, keeping its fraction of a second.
+func MarshalTime(t time.Time) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(t.UTC().Format(time.RFC3339Nano)))
+	})
+}
+
+func UnmarshalTime(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, ErrInvalidTime
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidTime
+	}
+
+	return t, nil
+}
+
+// MarshalDate writes the day of t in UTC as YYYY-MM-DD.
+func MarshalDate(t time.Time) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(t.UTC().Format(dateLayout)))
+	})
+}
+
+// UnmarshalDate returns the start of the day, in UTC, of a YYYY-MM-DD string.
+func UnmarshalDate(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, ErrInvalidDate
+	}
+
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+
+	return t, nil
+}
@@ -0,0 +1,73 @@
+package entity_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	pentity "github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTime(t *testing.T) {
+	at := time.Date(2019, 7, 3, 12, 30, 15, 250000000, time.FixedZone("BRT", -3*60*60))
+
+	// succeed
+	{
+		var b bytes.Buffer
+		entity.MarshalTime(at).MarshalGQL(&b)
+		assert.Equal(t, `"2019-07-03T15:30:15.25Z"`, b.String())
+
+		got, err := entity.UnmarshalTime("2019-07-03T12:30:15.25-03:00")
+		assert.Nil(t, err)
+		assert.True(t, at.Equal(got))
+	}
+
+	// fails if not an RFC3339 string
+	{
+		_, err := entity.UnmarshalTime("2019-07-03")
+		assert.Equal(t, entity.ErrInvalidTime, err)
+
+		_, err = entity.UnmarshalTime(1562167815)
+		assert.Equal(t, entity.ErrInvalidTime, err)
+	}
+
+	// carried by the converters
+	{
+		u := entity.NewUser(&pentity.User{ID: 1, Created: at, Updated: at.Add(time.Hour)})
+		assert.Equal(t, at, u.CreatedAt)
+		assert.Equal(t, at.Add(time.Hour), u.UpdatedAt)
+
+		e := entity.NewEmail(&pentity.Email{ID: 1, UserID: 1, Created: at})
+		assert.Equal(t, at, e.CreatedAt)
+	}
+}
+
+func TestDate(t *testing.T) {
+	// succeed
+	{
+		var b bytes.Buffer
+		entity.MarshalDate(time.Date(2019, 7, 3, 23, 30, 0, 0, time.FixedZone("BRT", -3*60*60))).MarshalGQL(&b)
+		assert.Equal(t, `"2019-07-04"`, b.String())
+
+		got, err := entity.UnmarshalDate("2019-07-03")
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2019, 7, 3, 0, 0, 0, 0, time.UTC), got)
+	}
+
+	// fails if not a YYYY-MM-DD string
+	{
+		_, err := entity.UnmarshalDate("07/01/2019")
+		assert.Equal(t, entity.ErrInvalidDate, err)
+
+		_, err = entity.UnmarshalDate("2019-02-30")
+		assert.Equal(t, entity.ErrInvalidDate, err)
+
+		_, err = entity.UnmarshalDate("2019-07-03T12:30:15Z")
+		assert.Equal(t, entity.ErrInvalidDate, err)
+
+		_, err = entity.UnmarshalDate(20190703)
+		assert.Equal(t, entity.ErrInvalidDate, err)
+	}
+}
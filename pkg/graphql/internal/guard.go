@@ -0,0 +1,53 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rafaelsq/errors"
+	"github.com/vektah/gqlparser/ast"
+	"github.com/vektah/gqlparser/gqlerror"
+)
+
+// guardedSchema answers the operations its guard rejects with the guard's response instead of running them.
+type guardedSchema struct {
+	graphql.ExecutableSchema
+	guard func(ctx context.Context, op *ast.OperationDefinition) *graphql.Response
+}
+
+func (s *guardedSchema) Query(ctx context.Context, op *ast.OperationDefinition) *graphql.Response {
+	if resp := s.guard(ctx, op); resp != nil {
+		return resp
+	}
+
+	return s.ExecutableSchema.Query(ctx, op)
+}
+
+func (s *guardedSchema) Mutation(ctx context.Context, op *ast.OperationDefinition) *graphql.Response {
+	if resp := s.guard(ctx, op); resp != nil {
+		return resp
+	}
+
+	return s.ExecutableSchema.Mutation(ctx, op)
+}
+
+func (s *guardedSchema) Subscription(ctx context.Context, op *ast.OperationDefinition) func() *graphql.Response {
+	if resp := s.guard(ctx, op); resp != nil {
+		return graphql.OneShot(resp)
+	}
+
+	return s.ExecutableSchema.Subscription(ctx, op)
+}
+
+// rejection is the response to an operation refused with err, one of the coded errors;
+// extensions detail why.
+func rejection(err *errors.Error, extensions map[string]interface{}) *graphql.Response {
+	extensions["code"] = err.Args["code"]
+
+	return &graphql.Response{
+		Errors: gqlerror.List{{
+			Message:    err.Error(),
+			Extensions: extensions,
+		}},
+	}
+}
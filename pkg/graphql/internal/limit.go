@@ -0,0 +1,112 @@
+package graphql
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/resolver"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/errors"
+	"github.com/vektah/gqlparser/ast"
+)
+
+var (
+	ErrTooDeep    = errors.New("operation is too deep").SetArg("code", "qdepth")
+	ErrTooComplex = errors.New("operation is too complex").SetArg("code", "qcomplex")
+)
+
+// Limits bounds the operations a schema runs; a zero bound is not enforced.
+type Limits struct {
+	// MaxDepth is how deeply fields may nest, introspection aside.
+	MaxDepth int
+
+	// MaxComplexity caps the summed cost of an operation's fields; see NewComplexity.
+	MaxComplexity int
+}
+
+// NewComplexity costs each field 1 plus its selection, except the pages of users
+// and emails, whose selection costs once per item asked by first or last.
+func NewComplexity() ComplexityRoot {
+	var c ComplexityRoot
+	c.Query.Users = func(childComplexity int, first *int, after *string, last *int, before *string, limit *int, includeDeleted *bool, filter *entity.UserFilter, orderBy *entity.UserOrder) int {
+		page := resolver.UsersPage(first, after, last, before, limit)
+		return pageComplexity(childComplexity, page.First, page.Last, iface.FilterUsersDefaultLimit)
+	}
+	c.User.Emails = func(childComplexity int, first *int, after *string, last *int, before *string, includeDeleted *bool) int {
+		return pageComplexity(childComplexity, first, last, iface.FilterEmailsDefaultLimit)
+	}
+
+	return c
+}
+
+// pageComplexity prices a page as the resolvers read it: without a size, or with 0, it is the storage default.
+func pageComplexity(childComplexity int, first, last *int, defaultLimit uint) int {
+	items := int(defaultLimit)
+	if first != nil && *first > 0 {
+		items = *first
+	} else if last != nil && *last > 0 {
+		items = *last
+	}
+
+	if childComplexity > 0 && items > (math.MaxInt32-1)/childComplexity {
+		return math.MaxInt32
+	}
+
+	return 1 + items*childComplexity
+}
+
+// NewLimitedSchema returns es, answering the operations that exceed limits with
+// a coded error instead of running them.
+func NewLimitedSchema(es graphql.ExecutableSchema, limits Limits) graphql.ExecutableSchema {
+	return &guardedSchema{es, func(ctx context.Context, op *ast.OperationDefinition) *graphql.Response {
+		if limits.MaxDepth > 0 {
+			if d := depth(op.SelectionSet); d > limits.MaxDepth {
+				return rejection(ErrTooDeep, map[string]interface{}{"depth": d, "limit": limits.MaxDepth})
+			}
+		}
+
+		if limits.MaxComplexity > 0 {
+			var vars map[string]interface{}
+			if reqCtx := graphql.GetRequestContext(ctx); reqCtx != nil {
+				vars = reqCtx.Variables
+			}
+
+			if c := complexity.Calculate(es, op, vars); c > limits.MaxComplexity {
+				return rejection(ErrTooComplex, map[string]interface{}{"complexity": c, "limit": limits.MaxComplexity})
+			}
+		}
+
+		return nil
+	}}
+}
+
+// depth is how deeply the fields of set nest, not counting introspection.
+func depth(set ast.SelectionSet) int {
+	max := 0
+	for _, selection := range set {
+		var d int
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			d = 1 + depth(sel.SelectionSet)
+		case *ast.InlineFragment:
+			d = depth(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				d = depth(sel.Definition.SelectionSet)
+			}
+		}
+
+		if d > max {
+			max = d
+		}
+	}
+
+	return max
+}
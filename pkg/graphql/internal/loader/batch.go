@@ -0,0 +1,88 @@
+package loader
+
+import (
+	"sync"
+	"time"
+)
+
+// fetchFunc returns the values found for keys; keys it leaves out load as nil.
+type fetchFunc func(keys []int64) (map[int64]interface{}, error)
+
+// batch coalesces the loads made within wait into one fetch of up to maxBatch
+// keys; each key is fetched once and its result kept for the batch's lifetime.
+type batch struct {
+	fetch    fetchFunc
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	results map[int64]*result
+	pending []int64
+}
+
+type result struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+func newBatch(fetch fetchFunc, wait time.Duration, maxBatch int) *batch {
+	return &batch{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		results:  make(map[int64]*result),
+	}
+}
+
+// load blocks until the batch holding key is fetched.
+func (b *batch) load(key int64) (interface{}, error) {
+	b.mu.Lock()
+	r, has := b.results[key]
+	if !has {
+		r = &result{done: make(chan struct{})}
+		b.results[key] = r
+
+		b.pending = append(b.pending, key)
+		switch len(b.pending) {
+		case b.maxBatch:
+			go b.run(b.take())
+		case 1:
+			time.AfterFunc(b.wait, func() {
+				b.mu.Lock()
+				keys := b.take()
+				b.mu.Unlock()
+
+				b.run(keys)
+			})
+		}
+	}
+	b.mu.Unlock()
+
+	<-r.done
+	return r.value, r.err
+}
+
+// take empties the pending keys; b.mu must be held.
+func (b *batch) take() []int64 {
+	keys := b.pending
+	b.pending = nil
+	return keys
+}
+
+func (b *batch) run(keys []int64) {
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := b.fetch(keys)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, key := range keys {
+		r := b.results[key]
+		r.value, r.err = values[key], err
+		close(r.done)
+	}
+}
@@ -0,0 +1,124 @@
+package loader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+)
+
+const (
+	wait     = time.Millisecond
+	maxBatch = 100
+)
+
+type ctxKey struct{}
+
+// Loaders batches the service lookups of one GraphQL request.
+type Loaders struct {
+	ctx     context.Context
+	service iface.Service
+
+	users *batch
+
+	mu     sync.Mutex
+	emails map[iface.FilterEmails]*batch
+}
+
+// New returns the loaders for a request made with ctx.
+func New(ctx context.Context, service iface.Service) *Loaders {
+	l := &Loaders{
+		ctx:     ctx,
+		service: service,
+		emails:  make(map[iface.FilterEmails]*batch),
+	}
+	l.users = newBatch(l.fetchUsers, wait, maxBatch)
+
+	return l
+}
+
+// With returns a copy of ctx holding l.
+func With(ctx context.Context, l *Loaders) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// From returns the loaders of the request; without them, it returns loaders for ctx alone.
+func From(ctx context.Context, service iface.Service) *Loaders {
+	if l, ok := ctx.Value(ctxKey{}).(*Loaders); ok {
+		return l
+	}
+
+	return New(ctx, service)
+}
+
+// User loads the user as GetUserByID would, batching the IDs of the request into GetUsersByID.
+func (l *Loaders) User(userID int64) (*entity.User, error) {
+	value, err := l.users.load(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return nil, iface.ErrNotFound
+	}
+
+	return value.(*entity.User), nil
+}
+
+// Emails loads a user's page of emails as FilterEmails would, batching the
+// users of the request that share the rest of the filter into FilterEmailsByUserIDs.
+func (l *Loaders) Emails(filter iface.FilterEmails) (*iface.EmailsPage, error) {
+	userID := filter.UserID
+	filter.UserID = 0
+
+	l.mu.Lock()
+	b, has := l.emails[filter]
+	if !has {
+		b = newBatch(func(userIDs []int64) (map[int64]interface{}, error) {
+			return l.fetchEmails(filter, userIDs)
+		}, wait, maxBatch)
+		l.emails[filter] = b
+	}
+	l.mu.Unlock()
+
+	value, err := b.load(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return &iface.EmailsPage{Emails: []*entity.Email{}}, nil
+	}
+
+	return value.(*iface.EmailsPage), nil
+}
+
+func (l *Loaders) fetchUsers(IDs []int64) (map[int64]interface{}, error) {
+	users, err := l.service.GetUsersByID(l.ctx, IDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[int64]interface{}, len(users))
+	for _, user := range users {
+		values[user.ID] = user
+	}
+
+	return values, nil
+}
+
+func (l *Loaders) fetchEmails(filter iface.FilterEmails, userIDs []int64) (map[int64]interface{}, error) {
+	pages, err := l.service.FilterEmailsByUserIDs(l.ctx, filter, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[int64]interface{}, len(pages))
+	for userID, page := range pages {
+		values[userID] = page
+	}
+
+	return values, nil
+}
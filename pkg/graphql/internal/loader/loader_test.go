@@ -0,0 +1,134 @@
+package loader_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/loader"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	// succeed
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().
+			GetUsersByID(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, IDs ...int64) ([]*entity.User, error) {
+				assert.ElementsMatch(t, []int64{1, 2, 3}, IDs)
+				return []*entity.User{{ID: 1}, {ID: 2}}, nil
+			})
+
+		l := loader.New(ctx, m)
+
+		var wg sync.WaitGroup
+		for _, ID := range []int64{1, 2, 3, 1} {
+			wg.Add(1)
+			go func(ID int64) {
+				defer wg.Done()
+
+				u, err := l.User(ID)
+				if ID == 3 {
+					assert.Nil(t, u)
+					assert.Equal(t, iface.ErrNotFound, err)
+					return
+				}
+
+				assert.Nil(t, err)
+				assert.Equal(t, ID, u.ID)
+			}(ID)
+		}
+		wg.Wait()
+
+		// loaded users are kept
+		u, err := l.User(2)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), u.ID)
+	}
+
+	// fails if service fails
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().GetUsersByID(gomock.Any(), int64(1)).Return(nil, fmt.Errorf("opz"))
+
+		u, err := loader.New(ctx, m).User(1)
+		assert.Nil(t, u)
+		assert.Equal(t, "opz", err.Error())
+	}
+}
+
+func TestEmails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	// succeed
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().
+			FilterEmailsByUserIDs(gomock.Any(), iface.FilterEmails{Limit: 2}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ iface.FilterEmails, userIDs []int64) (map[int64]*iface.EmailsPage, error) {
+				assert.ElementsMatch(t, []int64{1, 2}, userIDs)
+				return map[int64]*iface.EmailsPage{
+					1: {Emails: []*entity.Email{{ID: 3, UserID: 1}}, Next: "next"},
+				}, nil
+			})
+		m.EXPECT().
+			FilterEmailsByUserIDs(gomock.Any(), iface.FilterEmails{Limit: 1}, []int64{1}).
+			Return(map[int64]*iface.EmailsPage{1: {Emails: []*entity.Email{}}}, nil)
+
+		l := loader.New(ctx, m)
+
+		var wg sync.WaitGroup
+		for _, filter := range []iface.FilterEmails{{UserID: 1, Limit: 2}, {UserID: 2, Limit: 2}, {UserID: 1, Limit: 1}} {
+			wg.Add(1)
+			go func(filter iface.FilterEmails) {
+				defer wg.Done()
+
+				page, err := l.Emails(filter)
+				assert.Nil(t, err)
+				if filter.UserID == 1 && filter.Limit == 2 {
+					assert.Len(t, page.Emails, 1)
+					assert.Equal(t, "next", page.Next)
+					return
+				}
+
+				assert.Len(t, page.Emails, 0)
+				assert.Empty(t, page.Next)
+			}(filter)
+		}
+		wg.Wait()
+	}
+
+	// fails if service fails
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().
+			FilterEmailsByUserIDs(gomock.Any(), iface.FilterEmails{}, []int64{1}).
+			Return(nil, fmt.Errorf("opz"))
+
+		page, err := loader.New(ctx, m).Emails(iface.FilterEmails{UserID: 1})
+		assert.Nil(t, page)
+		assert.Equal(t, "opz", err.Error())
+	}
+}
+
+func TestFrom(t *testing.T) {
+	ctx := context.Background()
+	l := loader.New(ctx, nil)
+
+	assert.True(t, l == loader.From(loader.With(ctx, l), nil))
+	assert.False(t, l == loader.From(ctx, nil))
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/mail"
-	"strconv"
+	"strings"
 
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/log"
 	"github.com/rafaelsq/errors"
-	"github.com/vektah/gqlparser/gqlerror"
+)
+
+var (
+	ErrEmptyName      = errors.New("empty name").SetArg("code", "ename")
+	ErrInvalidVersion = errors.New("invalid expectedVersion").SetArg("code", "iver")
+	ErrInvalidAddress = errors.New("invalid email address").SetArg("code", "iaddr")
 )
 
 func NewMutation(service iface.Service) *Mutation {
@@ -23,51 +28,175 @@ type Mutation struct {
 	service iface.Service
 }
 
+// userErrors reports err, one of the coded errors, as a problem with the input field.
+func userErrors(err error, field string) []*entity.UserError {
+	e := err.(*errors.Error)
+	return []*entity.UserError{{
+		Field:   []string{"input", field},
+		Message: e.Error(),
+		Code:    e.Args["code"].(string),
+	}}
+}
+
+// user loads the user a mutation wrote, so its payload carries every field.
+func (m *Mutation) user(ctx context.Context, userID int64) (*entity.UserResponse, error) {
+	u, err := m.service.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Log(errors.New("fail to get user").SetParent(err))
+		return nil, fmt.Errorf("service failed")
+	}
+
+	return &entity.UserResponse{User: entity.NewUser(u), UserErrors: []*entity.UserError{}}, nil
+}
+
 func (m *Mutation) AddUser(ctx context.Context, input entity.AddUserInput) (*entity.UserResponse, error) {
-	userID, err := m.service.AddUser(ctx, input.Name)
+	name := strings.TrimSpace(input.Name)
+	if len(name) == 0 {
+		return &entity.UserResponse{UserErrors: userErrors(ErrEmptyName, "name")}, nil
+	}
+
+	userID, err := m.service.AddUser(ctx, name)
 	if err != nil {
 		log.Log(errors.New("fail to add user").SetParent(err))
 		return nil, fmt.Errorf("service failed")
 	}
 
-	return &entity.UserResponse{User: &entity.User{ID: strconv.FormatInt(userID, 10)}}, nil
+	return m.user(ctx, userID)
 }
 
-func (m *Mutation) AddEmail(ctx context.Context, input entity.AddEmailInput) (*entity.EmailResponse, error) {
-	userID, err := strconv.ParseInt(input.UserID, 10, 64)
-	if err != nil || userID == 0 {
-		return nil, &gqlerror.Error{
-			Message: "invalid userID",
-			Extensions: map[string]interface{}{
-				"code": "-1",
-			},
+func (m *Mutation) UpdateUser(ctx context.Context, input entity.UpdateUserInput) (*entity.UserResponse, error) {
+	userID, err := entity.UserID(input.UserID)
+	if err != nil {
+		return &entity.UserResponse{UserErrors: userErrors(iface.ErrInvalidID, "userID")}, nil
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if len(name) == 0 {
+		return &entity.UserResponse{UserErrors: userErrors(ErrEmptyName, "name")}, nil
+	}
+
+	if input.ExpectedVersion < 1 {
+		return &entity.UserResponse{UserErrors: userErrors(ErrInvalidVersion, "expectedVersion")}, nil
+	}
+
+	err = m.service.UpdateUser(ctx, userID, int64(input.ExpectedVersion), name)
+	if err != nil {
+		switch er := errors.Cause(err); er {
+		case iface.ErrNotFound:
+			return &entity.UserResponse{UserErrors: userErrors(er, "userID")}, nil
+		case iface.ErrConflict:
+			return &entity.UserResponse{UserErrors: userErrors(er, "expectedVersion")}, nil
 		}
+
+		log.Log(errors.New("fail to update user").SetParent(err))
+		return nil, fmt.Errorf("service failed")
 	}
 
-	address, err := mail.ParseAddress(input.Address)
+	return m.user(ctx, userID)
+}
+
+func (m *Mutation) RestoreUser(ctx context.Context, input entity.RestoreUserInput) (*entity.UserResponse, error) {
+	userID, err := entity.UserID(input.UserID)
+	if err != nil {
+		return &entity.UserResponse{UserErrors: userErrors(iface.ErrInvalidID, "userID")}, nil
+	}
+
+	if input.ExpectedVersion < 1 {
+		return &entity.UserResponse{UserErrors: userErrors(ErrInvalidVersion, "expectedVersion")}, nil
+	}
+
+	err = m.service.RestoreUser(ctx, userID, int64(input.ExpectedVersion))
+	if err != nil {
+		switch er := errors.Cause(err); er {
+		case iface.ErrNotFound:
+			return &entity.UserResponse{UserErrors: userErrors(er, "userID")}, nil
+		case iface.ErrConflict:
+			return &entity.UserResponse{UserErrors: userErrors(er, "expectedVersion")}, nil
+		}
+
+		log.Log(errors.New("fail to restore user").SetParent(err))
+		return nil, fmt.Errorf("service failed")
+	}
+
+	return m.user(ctx, userID)
+}
+
+func (m *Mutation) DeleteUser(ctx context.Context, input entity.DeleteUserInput) (*entity.DeleteUserResponse, error) {
+	userID, err := entity.UserID(input.UserID)
 	if err != nil {
-		return nil, &gqlerror.Error{
-			Message: "invalid email address",
-			Extensions: map[string]interface{}{
-				"code": "-2",
-			},
+		return &entity.DeleteUserResponse{UserErrors: userErrors(iface.ErrInvalidID, "userID")}, nil
+	}
+
+	if input.ExpectedVersion < 1 {
+		return &entity.DeleteUserResponse{UserErrors: userErrors(ErrInvalidVersion, "expectedVersion")}, nil
+	}
+
+	err = m.service.DeleteUser(ctx, userID, int64(input.ExpectedVersion))
+	if err != nil {
+		switch er := errors.Cause(err); er {
+		case iface.ErrConflict:
+			return &entity.DeleteUserResponse{UserErrors: userErrors(er, "expectedVersion")}, nil
+		case iface.ErrNotFound:
+			return &entity.DeleteUserResponse{UserErrors: userErrors(er, "userID")}, nil
 		}
+
+		log.Log(errors.New("fail to delete user").SetParent(err))
+		return nil, fmt.Errorf("service failed")
+	}
+
+	deletedUserID := entity.NewID(entity.UserTypename, userID)
+	return &entity.DeleteUserResponse{DeletedUserID: &deletedUserID, UserErrors: []*entity.UserError{}}, nil
+}
+
+func (m *Mutation) AddEmail(ctx context.Context, input entity.AddEmailInput) (*entity.EmailResponse, error) {
+	userID, err := entity.UserID(input.UserID)
+	if err != nil {
+		return &entity.EmailResponse{UserErrors: userErrors(iface.ErrInvalidID, "userID")}, nil
+	}
+
+	address, err := mail.ParseAddress(input.Address)
+	if err != nil {
+		return &entity.EmailResponse{UserErrors: userErrors(ErrInvalidAddress, "address")}, nil
 	}
 
 	emailID, err := m.service.AddEmail(ctx, userID, address.Address)
 	if err != nil {
 		if er := errors.Cause(err); er == iface.ErrAlreadyExists {
-			return nil, &gqlerror.Error{
-				Message: er.Error(),
-				Extensions: map[string]interface{}{
-					"code": er.(*errors.Error).Args["code"].(string),
-				},
-			}
+			return &entity.EmailResponse{UserErrors: userErrors(er, "address")}, nil
 		}
 
 		log.Log(errors.New("fail to add email").SetParent(err))
 		return nil, fmt.Errorf("service failed")
 	}
 
-	return &entity.EmailResponse{Email: &entity.Email{ID: strconv.FormatInt(emailID, 10)}}, nil
+	emails, _, err := m.service.FilterEmails(ctx, iface.FilterEmails{EmailID: emailID})
+	if err == nil && len(emails) == 0 {
+		err = iface.ErrNotFound
+	}
+	if err != nil {
+		log.Log(errors.New("fail to get email").SetParent(err))
+		return nil, fmt.Errorf("service failed")
+	}
+
+	return &entity.EmailResponse{Email: entity.NewEmail(emails[0]), UserErrors: []*entity.UserError{}}, nil
+}
+
+func (m *Mutation) DeleteEmail(ctx context.Context, input entity.DeleteEmailInput) (*entity.DeleteEmailResponse, error) {
+	emailID, err := entity.EmailID(input.EmailID)
+	if err != nil {
+		return &entity.DeleteEmailResponse{UserErrors: userErrors(iface.ErrInvalidID, "emailID")}, nil
+	}
+
+	err = m.service.DeleteEmail(ctx, emailID)
+	if err != nil {
+		if er := errors.Cause(err); er == iface.ErrNotFound {
+			return &entity.DeleteEmailResponse{UserErrors: userErrors(er, "emailID")}, nil
+		}
+
+		log.Log(errors.New("fail to delete email").SetParent(err))
+		return nil, fmt.Errorf("service failed")
+	}
+
+	deletedEmailID := entity.NewID(entity.EmailTypename, emailID)
+	return &entity.DeleteEmailResponse{DeletedEmailID: &deletedEmailID, UserErrors: []*entity.UserError{}}, nil
 }
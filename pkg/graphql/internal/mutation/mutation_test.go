@@ -3,16 +3,28 @@ package mutation
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	pentity "github.com/rafaelsq/boiler/pkg/entity"
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/mock"
+	"github.com/rafaelsq/boiler/pkg/service"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/rafaelsq/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// assertUserError checks errs holds one error about the input field, with code.
+func assertUserError(t *testing.T, errs []*entity.UserError, field, code string) {
+	require.Len(t, errs, 1)
+	assert.Equal(t, []string{"input", field}, errs[0].Field)
+	assert.Equal(t, code, errs[0].Code)
+	assert.NotEmpty(t, errs[0].Message)
+}
+
 func TestAddUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -28,12 +40,24 @@ func TestAddUser(t *testing.T) {
 		name := "name"
 
 		service.EXPECT().AddUser(ctx, name).Return(int64(1), nil)
+		service.EXPECT().GetUserByID(ctx, int64(1)).Return(&pentity.User{ID: 1, Name: name, Version: 1}, nil)
 
 		u, err := m.AddUser(ctx, entity.AddUserInput{
-			Name: name,
+			Name: " " + name + " ",
+		})
+		assert.Nil(t, err)
+		assert.Len(t, u.UserErrors, 0)
+		assert.Equal(t, &entity.User{ID: entity.NewID(entity.UserTypename, 1), Name: name, Version: 1}, u.User)
+	}
+
+	// fails if name is empty
+	{
+		u, err := m.AddUser(ctx, entity.AddUserInput{
+			Name: " ",
 		})
 		assert.Nil(t, err)
-		assert.NotNil(t, u)
+		assert.Nil(t, u.User)
+		assertUserError(t, u.UserErrors, "name", "ename")
 	}
 
 	// fails if service fails
@@ -48,6 +72,308 @@ func TestAddUser(t *testing.T) {
 		assert.Equal(t, err.Error(), "service failed")
 		assert.Nil(t, u)
 	}
+
+	// fails if user could not be loaded
+	{
+		name := "name"
+
+		service.EXPECT().AddUser(ctx, name).Return(int64(1), nil)
+		service.EXPECT().GetUserByID(ctx, int64(1)).Return(nil, fmt.Errorf("opz"))
+
+		u, err := m.AddUser(ctx, entity.AddUserInput{
+			Name: name,
+		})
+		assert.Equal(t, err.Error(), "service failed")
+		assert.Nil(t, u)
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock.NewMockService(ctrl)
+
+	m := NewMutation(service)
+
+	ctx := context.TODO()
+
+	// succeed
+	{
+		userID := int64(12)
+		name := "name"
+
+		service.EXPECT().UpdateUser(ctx, userID, int64(2), name).Return(nil)
+		service.EXPECT().GetUserByID(ctx, userID).Return(&pentity.User{ID: userID, Name: name, Version: 3}, nil)
+
+		u, err := m.UpdateUser(ctx, entity.UpdateUserInput{
+			UserID:          entity.NewID(entity.UserTypename, userID),
+			Name:            " " + name + " ",
+			ExpectedVersion: 2,
+		})
+		assert.Nil(t, err)
+		assert.Len(t, u.UserErrors, 0)
+		assert.Equal(t, &entity.User{ID: entity.NewID(entity.UserTypename, 12), Name: name, Version: 3}, u.User)
+	}
+
+	// fails if userID is invalid
+	{
+		u, err := m.UpdateUser(ctx, entity.UpdateUserInput{
+			UserID: "0",
+			Name:   "name",
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, u.User)
+		assertUserError(t, u.UserErrors, "userID", "iid")
+	}
+
+	// fails if name is empty
+	{
+		u, err := m.UpdateUser(ctx, entity.UpdateUserInput{
+			UserID: entity.NewID(entity.UserTypename, 1),
+			Name:   " ",
+		})
+		assert.Nil(t, err)
+		assertUserError(t, u.UserErrors, "name", "ename")
+	}
+
+	// fails if expectedVersion is invalid
+	{
+		u, err := m.UpdateUser(ctx, entity.UpdateUserInput{
+			UserID: entity.NewID(entity.UserTypename, 1),
+			Name:   "name",
+		})
+		assert.Nil(t, err)
+		assertUserError(t, u.UserErrors, "expectedVersion", "iver")
+	}
+
+	// fails if version is stale
+	{
+		userID := int64(12)
+		name := "name"
+
+		service.EXPECT().UpdateUser(ctx, userID, int64(2), name).Return(iface.ErrConflict)
+
+		u, err := m.UpdateUser(ctx, entity.UpdateUserInput{
+			UserID:          entity.NewID(entity.UserTypename, userID),
+			Name:            name,
+			ExpectedVersion: 2,
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, u.User)
+		assertUserError(t, u.UserErrors, "expectedVersion", "c0")
+	}
+
+	// fails if user is not found
+	{
+		userID := int64(12)
+		name := "name"
+
+		service.EXPECT().UpdateUser(ctx, userID, int64(2), name).Return(iface.ErrNotFound)
+
+		u, err := m.UpdateUser(ctx, entity.UpdateUserInput{
+			UserID:          entity.NewID(entity.UserTypename, userID),
+			Name:            name,
+			ExpectedVersion: 2,
+		})
+		assert.Nil(t, err)
+		assertUserError(t, u.UserErrors, "userID", "e0")
+	}
+
+	// fails if service fails
+	{
+		userID := int64(12)
+		name := "name"
+
+		service.EXPECT().UpdateUser(ctx, userID, int64(2), name).Return(fmt.Errorf("opz"))
+
+		u, err := m.UpdateUser(ctx, entity.UpdateUserInput{
+			UserID:          entity.NewID(entity.UserTypename, userID),
+			Name:            name,
+			ExpectedVersion: 2,
+		})
+		assert.Equal(t, err.Error(), "service failed")
+		assert.Nil(t, u)
+	}
+}
+
+func TestRestoreUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock.NewMockService(ctrl)
+
+	m := NewMutation(service)
+
+	ctx := context.TODO()
+
+	// succeed
+	{
+		userID := int64(12)
+
+		service.EXPECT().RestoreUser(ctx, userID, int64(2)).Return(nil)
+		service.EXPECT().GetUserByID(ctx, userID).Return(&pentity.User{ID: userID, Name: "name", Version: 3}, nil)
+
+		u, err := m.RestoreUser(ctx, entity.RestoreUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 2})
+		assert.Nil(t, err)
+		assert.Len(t, u.UserErrors, 0)
+		assert.Equal(t, &entity.User{ID: entity.NewID(entity.UserTypename, 12), Name: "name", Version: 3}, u.User)
+	}
+
+	// fails if userID is invalid
+	{
+		u, err := m.RestoreUser(ctx, entity.RestoreUserInput{UserID: "0", ExpectedVersion: 2})
+		assert.Nil(t, err)
+		assertUserError(t, u.UserErrors, "userID", "iid")
+	}
+
+	// fails if expectedVersion is invalid
+	{
+		u, err := m.RestoreUser(ctx, entity.RestoreUserInput{UserID: entity.NewID(entity.UserTypename, 12)})
+		assert.Nil(t, err)
+		assertUserError(t, u.UserErrors, "expectedVersion", "iver")
+	}
+
+	// fails if version is stale
+	{
+		userID := int64(12)
+
+		service.EXPECT().RestoreUser(ctx, userID, int64(2)).Return(iface.ErrConflict)
+
+		u, err := m.RestoreUser(ctx, entity.RestoreUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 2})
+		assert.Nil(t, err)
+		assert.Nil(t, u.User)
+		assertUserError(t, u.UserErrors, "expectedVersion", "c0")
+	}
+
+	// fails if there is no deleted user
+	{
+		userID := int64(12)
+
+		service.EXPECT().RestoreUser(ctx, userID, int64(2)).Return(iface.ErrNotFound)
+
+		u, err := m.RestoreUser(ctx, entity.RestoreUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 2})
+		assert.Nil(t, err)
+		assert.Nil(t, u.User)
+		assertUserError(t, u.UserErrors, "userID", "e0")
+	}
+
+	// fails if service fails
+	{
+		userID := int64(12)
+
+		service.EXPECT().RestoreUser(ctx, userID, int64(2)).Return(fmt.Errorf("opz"))
+
+		u, err := m.RestoreUser(ctx, entity.RestoreUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 2})
+		assert.Equal(t, err.Error(), "service failed")
+		assert.Nil(t, u)
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock.NewMockService(ctrl)
+
+	m := NewMutation(service)
+
+	ctx := context.TODO()
+
+	// succeed
+	{
+		userID := int64(12)
+
+		service.EXPECT().DeleteUser(ctx, userID, int64(2)).Return(nil)
+
+		u, err := m.DeleteUser(ctx, entity.DeleteUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 2})
+		assert.Nil(t, err)
+		assert.Len(t, u.UserErrors, 0)
+		assert.Equal(t, entity.NewID(entity.UserTypename, 12), *u.DeletedUserID)
+	}
+
+	// fails if userID is invalid
+	{
+		u, err := m.DeleteUser(ctx, entity.DeleteUserInput{UserID: "0", ExpectedVersion: 2})
+		assert.Nil(t, err)
+		assert.Nil(t, u.DeletedUserID)
+		assertUserError(t, u.UserErrors, "userID", "iid")
+	}
+
+	// fails if expectedVersion is invalid
+	{
+		u, err := m.DeleteUser(ctx, entity.DeleteUserInput{UserID: entity.NewID(entity.UserTypename, 12)})
+		assert.Nil(t, err)
+		assertUserError(t, u.UserErrors, "expectedVersion", "iver")
+	}
+
+	// fails if version is stale
+	{
+		userID := int64(12)
+
+		service.EXPECT().DeleteUser(ctx, userID, int64(2)).Return(iface.ErrConflict)
+
+		u, err := m.DeleteUser(ctx, entity.DeleteUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 2})
+		assert.Nil(t, err)
+		assert.Nil(t, u.DeletedUserID)
+		assertUserError(t, u.UserErrors, "expectedVersion", "c0")
+	}
+
+	// fails if the user is missing or deleted
+	{
+		userID := int64(12)
+
+		service.EXPECT().DeleteUser(ctx, userID, int64(2)).Return(errors.New("could not delete user").SetParent(iface.ErrNotFound))
+
+		u, err := m.DeleteUser(ctx, entity.DeleteUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 2})
+		assert.Nil(t, err)
+		assert.Nil(t, u.DeletedUserID)
+		assertUserError(t, u.UserErrors, "userID", "e0")
+	}
+
+	// fails if service fails
+	{
+		userID := int64(12)
+
+		service.EXPECT().DeleteUser(ctx, userID, int64(2)).Return(fmt.Errorf("opz"))
+
+		u, err := m.DeleteUser(ctx, entity.DeleteUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 2})
+		assert.Equal(t, err.Error(), "service failed")
+		assert.Nil(t, u)
+	}
+}
+
+func TestDeleteUserNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	srv := service.New(memory.New())
+	userID, err := srv.AddUser(ctx, "John")
+	require.Nil(t, err)
+
+	m := NewMutation(srv)
+
+	// fails if the user is missing
+	{
+		u, err := m.DeleteUser(ctx, entity.DeleteUserInput{UserID: entity.NewID(entity.UserTypename, userID+1), ExpectedVersion: 1})
+		assert.Nil(t, err)
+		assert.Nil(t, u.DeletedUserID)
+		assertUserError(t, u.UserErrors, "userID", "e0")
+	}
+
+	// fails if the user is already deleted
+	{
+		input := entity.DeleteUserInput{UserID: entity.NewID(entity.UserTypename, userID), ExpectedVersion: 1}
+
+		u, err := m.DeleteUser(ctx, input)
+		assert.Nil(t, err)
+		assert.Len(t, u.UserErrors, 0)
+
+		input.ExpectedVersion = 2
+		u, err = m.DeleteUser(ctx, input)
+		assert.Nil(t, err)
+		assert.Nil(t, u.DeletedUserID)
+		assertUserError(t, u.UserErrors, "userID", "e0")
+	}
 }
 
 func TestAddEmail(t *testing.T) {
@@ -66,13 +392,19 @@ func TestAddEmail(t *testing.T) {
 		userID := int64(12)
 
 		service.EXPECT().AddEmail(ctx, userID, address).Return(int64(1), nil)
+		service.EXPECT().
+			FilterEmails(ctx, iface.FilterEmails{EmailID: 1}).
+			Return([]*pentity.Email{{ID: 1, UserID: userID, Address: address}}, "", nil)
 
 		u, err := m.AddEmail(ctx, entity.AddEmailInput{
-			UserID:  strconv.FormatInt(userID, 10),
+			UserID:  entity.NewID(entity.UserTypename, userID),
 			Address: address,
 		})
 		assert.Nil(t, err)
-		assert.Equal(t, u.Email.ID, "1")
+		assert.Len(t, u.UserErrors, 0)
+		assert.Equal(t, entity.NewID(entity.EmailTypename, 1), u.Email.ID)
+		assert.Equal(t, u.Email.Address, address)
+		assert.Equal(t, entity.NewID(entity.UserTypename, 12), u.Email.User.ID)
 	}
 
 	// fails if userID is invalid
@@ -84,21 +416,22 @@ func TestAddEmail(t *testing.T) {
 			UserID:  userID,
 			Address: address,
 		})
-		assert.Equal(t, err.Error(), "input: invalid userID")
-		assert.Nil(t, u)
+		assert.Nil(t, err)
+		assert.Nil(t, u.Email)
+		assertUserError(t, u.UserErrors, "userID", "iid")
 	}
 
 	// fails if email is invalid
 	{
 		address := "email"
-		userID := "1"
+		userID := entity.NewID(entity.UserTypename, 1)
 
 		u, err := m.AddEmail(ctx, entity.AddEmailInput{
 			UserID:  userID,
 			Address: address,
 		})
-		assert.Equal(t, err.Error(), "input: invalid email address")
-		assert.Nil(t, u)
+		assert.Nil(t, err)
+		assertUserError(t, u.UserErrors, "address", "iaddr")
 	}
 
 	// fails if service fails with duplicated
@@ -109,11 +442,12 @@ func TestAddEmail(t *testing.T) {
 		service.EXPECT().AddEmail(ctx, userID, address).Return(int64(0), iface.ErrAlreadyExists)
 
 		u, err := m.AddEmail(ctx, entity.AddEmailInput{
-			UserID:  strconv.FormatInt(userID, 10),
+			UserID:  entity.NewID(entity.UserTypename, userID),
 			Address: address,
 		})
-		assert.Equal(t, err.Error(), fmt.Sprintf("input: %v", iface.ErrAlreadyExists))
-		assert.Nil(t, u)
+		assert.Nil(t, err)
+		assert.Nil(t, u.Email)
+		assertUserError(t, u.UserErrors, "address", "s1")
 	}
 
 	// fails if service fails
@@ -124,10 +458,80 @@ func TestAddEmail(t *testing.T) {
 		service.EXPECT().AddEmail(ctx, userID, address).Return(int64(0), fmt.Errorf("opz"))
 
 		u, err := m.AddEmail(ctx, entity.AddEmailInput{
-			UserID:  strconv.FormatInt(userID, 10),
+			UserID:  entity.NewID(entity.UserTypename, userID),
+			Address: address,
+		})
+		assert.Equal(t, err.Error(), "service failed")
+		assert.Nil(t, u)
+	}
+
+	// fails if email could not be loaded
+	{
+		address := "email@email.com"
+		userID := int64(12)
+
+		service.EXPECT().AddEmail(ctx, userID, address).Return(int64(1), nil)
+		service.EXPECT().FilterEmails(ctx, iface.FilterEmails{EmailID: 1}).Return([]*pentity.Email{}, "", nil)
+
+		u, err := m.AddEmail(ctx, entity.AddEmailInput{
+			UserID:  entity.NewID(entity.UserTypename, userID),
 			Address: address,
 		})
 		assert.Equal(t, err.Error(), "service failed")
 		assert.Nil(t, u)
 	}
 }
+
+func TestDeleteEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock.NewMockService(ctrl)
+
+	m := NewMutation(service)
+
+	ctx := context.TODO()
+
+	// succeed
+	{
+		service.EXPECT().DeleteEmail(ctx, int64(3)).Return(nil)
+
+		e, err := m.DeleteEmail(ctx, entity.DeleteEmailInput{EmailID: entity.NewID(entity.EmailTypename, 3)})
+		assert.Nil(t, err)
+		assert.Len(t, e.UserErrors, 0)
+		assert.Equal(t, entity.NewID(entity.EmailTypename, 3), *e.DeletedEmailID)
+	}
+
+	// fails if emailID is invalid
+	{
+		e, err := m.DeleteEmail(ctx, entity.DeleteEmailInput{EmailID: "-3"})
+		assert.Nil(t, err)
+		assert.Nil(t, e.DeletedEmailID)
+		assertUserError(t, e.UserErrors, "emailID", "iid")
+	}
+
+	// fails if emailID is of another type
+	{
+		e, err := m.DeleteEmail(ctx, entity.DeleteEmailInput{EmailID: entity.NewID(entity.UserTypename, 3)})
+		assert.Nil(t, err)
+		assertUserError(t, e.UserErrors, "emailID", "iid")
+	}
+
+	// fails if email is not found
+	{
+		service.EXPECT().DeleteEmail(ctx, int64(3)).Return(iface.ErrNotFound)
+
+		e, err := m.DeleteEmail(ctx, entity.DeleteEmailInput{EmailID: entity.NewID(entity.EmailTypename, 3)})
+		assert.Nil(t, err)
+		assertUserError(t, e.UserErrors, "emailID", "e0")
+	}
+
+	// fails if service fails
+	{
+		service.EXPECT().DeleteEmail(ctx, int64(3)).Return(fmt.Errorf("opz"))
+
+		e, err := m.DeleteEmail(ctx, entity.DeleteEmailInput{EmailID: entity.NewID(entity.EmailTypename, 3)})
+		assert.Equal(t, err.Error(), "service failed")
+		assert.Nil(t, e)
+	}
+}
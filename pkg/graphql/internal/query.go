@@ -5,22 +5,60 @@ import (
 
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/resolver"
+	"github.com/rafaelsq/boiler/pkg/iface"
 )
 
-func NewQuery(ru *resolver.User) QueryResolver {
+func NewQuery(ru *resolver.User, re *resolver.Email) QueryResolver {
 	return &Query{
 		ru: ru,
+		re: re,
 	}
 }
 
 type Query struct {
 	ru *resolver.User
+	re *resolver.Email
 }
 
-func (r *Query) Users(ctx context.Context, limit *int) ([]*entity.User, error) {
-	return r.ru.Users(ctx, uint(*limit))
+func (r *Query) Users(ctx context.Context, first *int, after *string, last *int, before *string, limit *int, includeDeleted *bool, filter *entity.UserFilter, orderBy *entity.UserOrder) (*entity.UserConnection, error) {
+	page := resolver.UsersPage(first, after, last, before, limit)
+	return r.ru.Users(ctx, page, includeDeleted != nil && *includeDeleted, filter, orderBy)
 }
 
 func (r *Query) User(ctx context.Context, userID string) (*entity.User, error) {
 	return r.ru.User(ctx, userID)
 }
+
+func (r *Query) Email(ctx context.Context, emailID string) (*entity.Email, error) {
+	return r.re.Email(ctx, emailID)
+}
+
+// Node finds any node by its ID; one that does not exist is null, as Relay expects.
+func (r *Query) Node(ctx context.Context, id string) (entity.Node, error) {
+	typename, _, err := entity.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var node entity.Node
+	switch typename {
+	case entity.UserTypename:
+		var u *entity.User
+		if u, err = r.ru.User(ctx, id); err == nil {
+			node = u
+		}
+	case entity.EmailTypename:
+		var e *entity.Email
+		if e, err = r.re.Email(ctx, id); err == nil {
+			node = e
+		}
+	default:
+		err = iface.ErrInvalidID
+	}
+
+	if err == iface.ErrNotFound {
+		return nil, nil
+	}
+
+	return node, err
+}
@@ -4,6 +4,7 @@ package graphql
 import (
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/mutation"
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/resolver"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/subscription"
 	"github.com/rafaelsq/boiler/pkg/iface"
 )
 
@@ -16,25 +17,29 @@ type Resolver struct {
 }
 
 func (r *Resolver) Query() QueryResolver {
-	return NewQuery(resolver.NewUser(r.service))
+	return NewQuery(resolver.NewUser(r.service), resolver.NewEmail(r.service))
 }
 
 func (r *Resolver) Mutation() MutationResolver {
 	return mutation.NewMutation(r.service)
 }
 
+func (r *Resolver) Subscription() SubscriptionResolver {
+	return subscription.NewSubscription(r.service)
+}
+
 func (r *Resolver) User() UserResolver {
 	return resolver.NewUser(r.service)
 }
 
-func (r *Resolver) UserResponse() UserResponseResolver {
-	return resolver.NewResponse(r.service)
+func (r *Resolver) UserConnection() UserConnectionResolver {
+	return resolver.NewUser(r.service)
 }
 
 func (r *Resolver) Email() EmailResolver {
 	return resolver.NewEmail(r.service)
 }
 
-func (r *Resolver) EmailResponse() EmailResponseResolver {
-	return resolver.NewResponse(r.service)
+func (r *Resolver) EmailConnection() EmailConnectionResolver {
+	return resolver.NewEmail(r.service)
 }
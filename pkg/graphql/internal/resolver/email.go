@@ -2,9 +2,9 @@ package resolver
 
 import (
 	"context"
-	"strconv"
 
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/loader"
 	"github.com/rafaelsq/boiler/pkg/iface"
 )
 
@@ -19,20 +19,34 @@ type Email struct {
 }
 
 func (r *Email) User(ctx context.Context, e *entity.Email) (*entity.User, error) {
-	u, err := r.service.GetUserByEmail(ctx, e.Address)
+	userID, err := entity.UserID(e.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := loader.From(ctx, r.service).User(userID)
 	if err == nil {
 		return entity.NewUser(u), nil
 	}
-	return nil, Wrap(ctx, err, "fail to get user by email")
+	return nil, Wrap(ctx, err, "fail to get user")
+}
+
+func (r *Email) TotalCount(ctx context.Context, conn *entity.EmailConnection) (int, error) {
+	n, err := r.service.CountEmails(ctx, conn.Filter)
+	if err != nil {
+		return 0, Wrap(ctx, err, "fail to count emails")
+	}
+
+	return int(n), nil
 }
 
 func (r *Email) Email(ctx context.Context, rawEmailID string) (*entity.Email, error) {
-	emailID, err := strconv.ParseInt(rawEmailID, 10, 64)
-	if err != nil || emailID == 0 {
-		return nil, iface.ErrInvalidID
+	emailID, err := entity.EmailID(rawEmailID)
+	if err != nil {
+		return nil, err
 	}
 
-	emails, err := r.service.FilterEmails(ctx, iface.FilterEmails{EmailID: emailID})
+	emails, _, err := r.service.FilterEmails(ctx, iface.FilterEmails{EmailID: emailID})
 	if err != nil {
 		return nil, Wrap(ctx, err, "fail to filter emails")
 	}
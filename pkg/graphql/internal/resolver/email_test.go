@@ -3,7 +3,6 @@ package resolver_test
 import (
 	"errors"
 	"fmt"
-	"strconv"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -28,13 +27,13 @@ func TestEmailEmails(t *testing.T) {
 		r := resolver.NewEmail(m)
 
 		m.EXPECT().
-			GetUserByEmail(gomock.Any(), email.Address).
-			Return(user, nil)
+			GetUsersByID(gomock.Any(), user.ID).
+			Return([]*entity.User{user}, nil)
 
-		u, err := r.User(ctxDebug, &gentity.Email{Address: email.Address})
+		u, err := r.User(ctxDebug, gentity.NewEmail(&entity.Email{ID: email.ID, UserID: user.ID, Address: email.Address}))
 		assert.Nil(t, err)
 		assert.NotNil(t, u)
-		assert.Equal(t, u.ID, strconv.FormatInt(user.ID, 10))
+		assert.Equal(t, u.ID, gentity.NewID(gentity.UserTypename, user.ID))
 	}
 
 	// fails if service fails
@@ -45,14 +44,28 @@ func TestEmailEmails(t *testing.T) {
 		r := resolver.NewEmail(m)
 
 		m.EXPECT().
-			GetUserByEmail(gomock.Any(), email.Address).
+			GetUsersByID(gomock.Any(), int64(2)).
 			Return(nil, fmt.Errorf("opz"))
 
-		u, err := r.User(ctxDebug, &gentity.Email{Address: email.Address})
+		u, err := r.User(ctxDebug, gentity.NewEmail(&entity.Email{ID: email.ID, UserID: 2, Address: email.Address}))
 		assert.Nil(t, u)
 		assert.NotNil(t, err)
 		assert.Equal(t, err.Error(), "opz")
 	}
+
+	// fails if user is not found
+	{
+		m := mock.NewMockService(ctrl)
+		r := resolver.NewEmail(m)
+
+		m.EXPECT().
+			GetUsersByID(gomock.Any(), int64(2)).
+			Return([]*entity.User{}, nil)
+
+		u, err := r.User(ctxDebug, gentity.NewEmail(&entity.Email{ID: 4, UserID: 2, Address: "a@b.c"}))
+		assert.Nil(t, u)
+		assert.Equal(t, iface.ErrNotFound, err)
+	}
 }
 
 func TestEmailEmail(t *testing.T) {
@@ -70,12 +83,12 @@ func TestEmailEmail(t *testing.T) {
 			FilterEmails(gomock.Any(), iface.FilterEmails{
 				EmailID: email.ID,
 			}).
-			Return([]*entity.Email{email}, nil)
+			Return([]*entity.Email{email}, "", nil)
 
-		e, err := r.Email(ctxDebug, strconv.FormatInt(email.ID, 10))
+		e, err := r.Email(ctxDebug, gentity.NewID(gentity.EmailTypename, email.ID))
 		assert.Nil(t, err)
 		assert.NotNil(t, e)
-		assert.Equal(t, e.ID, strconv.FormatInt(email.ID, 10))
+		assert.Equal(t, e.ID, gentity.NewID(gentity.EmailTypename, email.ID))
 	}
 
 	// fails if invalid ID
@@ -85,7 +98,17 @@ func TestEmailEmail(t *testing.T) {
 		m := mock.NewMockService(ctrl)
 		r := resolver.NewEmail(m)
 
-		e, err := r.Email(ctxDebug, strconv.FormatInt(email.ID, 10))
+		e, err := r.Email(ctxDebug, gentity.NewID(gentity.EmailTypename, email.ID))
+		assert.Nil(t, e)
+		assert.Equal(t, iface.ErrInvalidID, err)
+	}
+
+	// fails if the ID is of a user
+	{
+		m := mock.NewMockService(ctrl)
+		r := resolver.NewEmail(m)
+
+		e, err := r.Email(ctxDebug, gentity.NewID(gentity.UserTypename, 5))
 		assert.Nil(t, e)
 		assert.Equal(t, iface.ErrInvalidID, err)
 	}
@@ -101,9 +124,9 @@ func TestEmailEmail(t *testing.T) {
 			FilterEmails(gomock.Any(), iface.FilterEmails{
 				EmailID: email.ID,
 			}).
-			Return(nil, errors.New("err"))
+			Return(nil, "", errors.New("err"))
 
-		e, err := r.Email(ctxDebug, strconv.FormatInt(email.ID, 10))
+		e, err := r.Email(ctxDebug, gentity.NewID(gentity.EmailTypename, email.ID))
 		assert.NotNil(t, err)
 		assert.Equal(t, err.Error(), "err")
 		assert.Nil(t, e)
@@ -120,10 +143,36 @@ func TestEmailEmail(t *testing.T) {
 			FilterEmails(gomock.Any(), iface.FilterEmails{
 				EmailID: email.ID,
 			}).
-			Return([]*entity.Email{}, nil)
+			Return([]*entity.Email{}, "", nil)
 
-		e, err := r.Email(ctxDebug, strconv.FormatInt(email.ID, 10))
+		e, err := r.Email(ctxDebug, gentity.NewID(gentity.EmailTypename, email.ID))
 		assert.Equal(t, err, iface.ErrNotFound)
 		assert.Nil(t, e)
 	}
 }
+
+func TestEmailTotalCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := iface.FilterEmails{UserID: 2, Limit: 1}
+
+	// succeed
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().CountEmails(gomock.Any(), filter).Return(int64(3), nil)
+
+		n, err := resolver.NewEmail(m).TotalCount(ctxDebug, &gentity.EmailConnection{Filter: filter})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, n)
+	}
+
+	// fails if service fails
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().CountEmails(gomock.Any(), filter).Return(int64(0), errors.New("opz"))
+
+		_, err := resolver.NewEmail(m).TotalCount(ctxDebug, &gentity.EmailConnection{Filter: filter})
+		assert.Equal(t, err.Error(), "opz")
+	}
+}
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+)
+
+// usersFilter returns the storage filter of the users matching by, listed by orderBy; both may be nil.
+func usersFilter(by *entity.UserFilter, orderBy *entity.UserOrder) iface.FilterUsers {
+	var filter iface.FilterUsers
+
+	if by != nil {
+		if by.Email != nil {
+			filter.Email = *by.Email
+		}
+
+		if by.EmailDomain != nil {
+			filter.EmailDomain = *by.EmailDomain
+		}
+
+		if by.NamePrefix != nil {
+			filter.NamePrefix = *by.NamePrefix
+		}
+
+		if by.CreatedFrom != nil {
+			filter.CreatedFrom = *by.CreatedFrom
+		}
+
+		// the last day is included up to its end
+		if by.CreatedTo != nil {
+			filter.CreatedBefore = by.CreatedTo.AddDate(0, 0, 1)
+		}
+	}
+
+	if orderBy != nil {
+		switch orderBy.Field {
+		case entity.UserOrderFieldName:
+			filter.OrderBy = iface.UserOrderName
+		case entity.UserOrderFieldCreated:
+			filter.OrderBy = iface.UserOrderCreated
+		}
+
+		filter.Desc = orderBy.Direction != nil && *orderBy.Direction == entity.OrderDirectionDesc
+	}
+
+	return filter
+}
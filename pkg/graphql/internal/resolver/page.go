@@ -0,0 +1,98 @@
+package resolver
+
+import (
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/rafaelsq/errors"
+)
+
+// ErrInvalidPage is returned for a negative page size or for first/after combined with last/before.
+var ErrInvalidPage = errors.New("invalid page arguments").SetArg("code", "ipg")
+
+// Page holds the Relay arguments of a connection field.
+type Page struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// UsersDefaultSize is the size of a page of Query.users asked without first, last or limit.
+const UsersDefaultSize = 100
+
+// UsersPage returns the page of Query.users; its deprecated limit is first, and
+// without either, or last, the page holds UsersDefaultSize users.
+func UsersPage(first *int, after *string, last *int, before *string, limit *int) Page {
+	if first == nil {
+		first = limit
+	}
+
+	page := Page{First: first, After: after, Last: last, Before: before}
+	if first == nil && last == nil {
+		size := UsersDefaultSize
+		if page.backward() {
+			page.Last = &size
+		} else {
+			page.First = &size
+		}
+	}
+
+	return page
+}
+
+func (p Page) backward() bool {
+	return p.Last != nil || p.Before != nil
+}
+
+// empty reports whether the page asks for no rows, which filter's 0 limit would not tell
+// from the storage default.
+func (p Page) empty() bool {
+	return (p.First != nil && *p.First == 0) || (p.Last != nil && *p.Last == 0)
+}
+
+// filter returns the limit, cursor and direction of the page; a 0 limit is the storage
+// default, and an empty page is not read at all.
+func (p Page) filter() (uint, string, bool, error) {
+	if p.backward() {
+		if p.First != nil || p.After != nil || (p.Last != nil && *p.Last < 0) {
+			return 0, "", false, ErrInvalidPage
+		}
+
+		var cursor string
+		if p.Before != nil {
+			cursor = *p.Before
+		}
+
+		var limit uint
+		if p.Last != nil {
+			limit = uint(*p.Last)
+		}
+
+		return limit, cursor, true, nil
+	}
+
+	if p.First != nil && *p.First < 0 {
+		return 0, "", false, ErrInvalidPage
+	}
+
+	var cursor string
+	if p.After != nil {
+		cursor = *p.After
+	}
+
+	var limit uint
+	if p.First != nil {
+		limit = uint(*p.First)
+	}
+
+	return limit, cursor, false, nil
+}
+
+// info describes the page from startID to endID given the storage cursor of
+// the following page; rows past the page's own cursor are assumed to exist.
+func (p Page) info(startID, endID int64, next string) *entity.PageInfo {
+	if p.backward() {
+		return entity.NewPageInfo(startID, endID, len(next) != 0, p.Before != nil)
+	}
+
+	return entity.NewPageInfo(startID, endID, p.After != nil, len(next) != 0)
+}
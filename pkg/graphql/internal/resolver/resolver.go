@@ -10,7 +10,7 @@ import (
 )
 
 func Wrap(ctx context.Context, err error, args ...string) error {
-	if er := errors.Cause(err); err == iface.ErrNotFound {
+	if er := errors.Cause(err); er == iface.ErrNotFound || er == iface.ErrInvalidCursor {
 		return er
 	}
 
@@ -6,12 +6,15 @@ import (
 	"testing"
 
 	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/errors"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestWrap(t *testing.T) {
 	assert.Equal(t, iface.ErrNotFound, Wrap(context.TODO(), iface.ErrNotFound))
 
+	assert.Equal(t, iface.ErrInvalidCursor, Wrap(context.TODO(), errors.New("could not filter users").SetParent(iface.ErrInvalidCursor)))
+
 	assert.Equal(t, "service failed", Wrap(context.TODO(), fmt.Errorf("opz"), "fail").Error())
 
 	assert.Equal(t, "opz", Wrap(context.WithValue(context.TODO(), "debug", true), fmt.Errorf("opz")).Error())
@@ -2,9 +2,9 @@ package resolver
 
 import (
 	"context"
-	"strconv"
 
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/loader"
 	"github.com/rafaelsq/boiler/pkg/iface"
 )
 
@@ -19,9 +19,9 @@ type User struct {
 }
 
 func (r *User) User(ctx context.Context, rawUserID string) (*entity.User, error) {
-	userID, err := strconv.ParseInt(rawUserID, 10, 64)
-	if err != nil || userID == 0 {
-		return nil, iface.ErrInvalidID
+	userID, err := entity.UserID(rawUserID)
+	if err != nil {
+		return nil, err
 	}
 
 	u, err := r.service.GetUserByID(ctx, userID)
@@ -31,32 +31,114 @@ func (r *User) User(ctx context.Context, rawUserID string) (*entity.User, error)
 	return nil, Wrap(ctx, err, "fail to get user")
 }
 
-func (r *User) Users(ctx context.Context, limit uint) ([]*entity.User, error) {
-	us, err := r.service.FilterUsers(ctx, iface.FilterUsers{Limit: limit})
-	if err == nil {
-		users := make([]*entity.User, 0, len(us))
-		for _, u := range us {
-			users = append(users, entity.NewUser(u))
+func (r *User) Users(ctx context.Context, page Page, includeDeleted bool, by *entity.UserFilter, orderBy *entity.UserOrder) (*entity.UserConnection, error) {
+	filter := usersFilter(by, orderBy)
+
+	var err error
+	filter.Limit, filter.Cursor, filter.Backward, err = page.filter()
+	if err != nil {
+		return nil, err
+	}
+	filter.IncludeDeleted = includeDeleted
+
+	if page.empty() {
+		return &entity.UserConnection{
+			Edges:    []*entity.UserEdge{},
+			Nodes:    []*entity.User{},
+			PageInfo: page.info(0, 0, ""),
+			Filter:   filter,
+		}, nil
+	}
+
+	us, next, err := r.service.FilterUsers(ctx, filter)
+	if err != nil {
+		return nil, Wrap(ctx, err, "fail to filter users")
+	}
+
+	conn := &entity.UserConnection{
+		Edges:  make([]*entity.UserEdge, 0, len(us)),
+		Nodes:  make([]*entity.User, 0, len(us)),
+		Filter: filter,
+	}
+
+	var startID, endID int64
+	for i, u := range us {
+		if i == 0 {
+			startID = u.ID
 		}
-		return users, nil
+		endID = u.ID
+
+		node := entity.NewUser(u)
+		conn.Edges = append(conn.Edges, &entity.UserEdge{Node: node, Cursor: iface.EncodeCursor(u.ID)})
+		conn.Nodes = append(conn.Nodes, node)
 	}
-	return nil, Wrap(ctx, err, "fail to filter users")
+	conn.PageInfo = page.info(startID, endID, next)
+
+	return conn, nil
 }
 
-func (r *User) Emails(ctx context.Context, u *entity.User) ([]*entity.Email, error) {
-	userID, err := strconv.ParseInt(u.ID, 10, 64)
-	if err != nil || userID == 0 {
-		return nil, iface.ErrInvalidID
+func (r *User) TotalCount(ctx context.Context, conn *entity.UserConnection) (int, error) {
+	n, err := r.service.CountUsers(ctx, conn.Filter)
+	if err != nil {
+		return 0, Wrap(ctx, err, "fail to count users")
 	}
 
-	es, err := r.service.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
-	if err == nil {
-		emails := make([]*entity.Email, 0, len(es))
-		for _, e := range es {
-			emails = append(emails, entity.NewEmail(e))
+	return int(n), nil
+}
+
+func (r *User) Emails(ctx context.Context, u *entity.User, first *int, after *string, last *int, before *string, includeDeleted *bool) (*entity.EmailConnection, error) {
+	userID, err := entity.UserID(u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := Page{First: first, After: after, Last: last, Before: before}
+	limit, cursor, backward, err := page.filter()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := iface.FilterEmails{
+		UserID:         userID,
+		Limit:          limit,
+		Cursor:         cursor,
+		Backward:       backward,
+		IncludeDeleted: includeDeleted != nil && *includeDeleted,
+	}
+
+	if page.empty() {
+		return &entity.EmailConnection{
+			Edges:    []*entity.EmailEdge{},
+			Nodes:    []*entity.Email{},
+			PageInfo: page.info(0, 0, ""),
+			Filter:   filter,
+		}, nil
+	}
+
+	emailsPage, err := loader.From(ctx, r.service).Emails(filter)
+	if err != nil {
+		return nil, Wrap(ctx, err, "fail to filter emails")
+	}
+	es, next := emailsPage.Emails, emailsPage.Next
+
+	conn := &entity.EmailConnection{
+		Edges:  make([]*entity.EmailEdge, 0, len(es)),
+		Nodes:  make([]*entity.Email, 0, len(es)),
+		Filter: filter,
+	}
+
+	var startID, endID int64
+	for i, e := range es {
+		if i == 0 {
+			startID = e.ID
 		}
-		return emails, nil
+		endID = e.ID
+
+		node := entity.NewEmail(e)
+		conn.Edges = append(conn.Edges, &entity.EmailEdge{Node: node, Cursor: iface.EncodeCursor(e.ID)})
+		conn.Nodes = append(conn.Nodes, node)
 	}
+	conn.PageInfo = page.info(startID, endID, next)
 
-	return nil, Wrap(ctx, err, "fail to filter emails")
+	return conn, nil
 }
@@ -3,8 +3,8 @@ package resolver_test
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/rafaelsq/boiler/pkg/entity"
@@ -12,6 +12,8 @@ import (
 	"github.com/rafaelsq/boiler/pkg/graphql/internal/resolver"
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/mock"
+	"github.com/rafaelsq/boiler/pkg/service"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,10 +38,10 @@ func TestUserUser(t *testing.T) {
 			GetUserByID(gomock.Any(), user.ID).
 			Return(user, nil)
 
-		u, err := r.User(ctxDebug, strconv.FormatInt(user.ID, 10))
+		u, err := r.User(ctxDebug, gentity.NewID(gentity.UserTypename, user.ID))
 		assert.Nil(t, err)
 		assert.NotNil(t, u)
-		assert.Equal(t, strconv.FormatInt(user.ID, 10), u.ID)
+		assert.Equal(t, gentity.NewID(gentity.UserTypename, user.ID), u.ID)
 		assert.Equal(t, user.Name, u.Name)
 	}
 
@@ -64,13 +66,25 @@ func TestUserUser(t *testing.T) {
 			GetUserByID(gomock.Any(), user.ID).
 			Return(nil, fmt.Errorf("opz"))
 
-		u, err := r.User(ctxDebug, strconv.FormatInt(user.ID, 10))
+		u, err := r.User(ctxDebug, gentity.NewID(gentity.UserTypename, user.ID))
 		assert.Nil(t, u)
 		assert.NotNil(t, err)
 		assert.Equal(t, err.Error(), "opz")
 	}
 }
 
+func intp(i int) *int {
+	return &i
+}
+
+func strp(s string) *string {
+	return &s
+}
+
+func timep(t time.Time) *time.Time {
+	return &t
+}
+
 func TestUserUsers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -84,12 +98,135 @@ func TestUserUsers(t *testing.T) {
 
 		m.EXPECT().
 			FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 2}).
-			Return([]*entity.User{user}, nil)
+			Return([]*entity.User{user}, "", nil)
 
-		users, err := r.Users(ctxDebug, 2)
+		users, err := r.Users(ctxDebug, resolver.Page{First: intp(2)}, false, nil, nil)
 		assert.Nil(t, err)
 		assert.NotNil(t, users)
-		assert.Equal(t, len(users), 1)
+		assert.Equal(t, len(users.Nodes), 1)
+		assert.Equal(t, len(users.Edges), 1)
+		assert.Equal(t, gentity.NewID(gentity.UserTypename, 4), users.Edges[0].Node.ID)
+		assert.Equal(t, iface.EncodeCursor(user.ID), users.Edges[0].Cursor)
+		assert.False(t, users.PageInfo.HasNextPage)
+		assert.False(t, users.PageInfo.HasPreviousPage)
+		assert.Equal(t, iface.EncodeCursor(user.ID), *users.PageInfo.StartCursor)
+		assert.Equal(t, iface.EncodeCursor(user.ID), *users.PageInfo.EndCursor)
+	}
+
+	// has next page
+	{
+		users := []*entity.User{{ID: 4}, {ID: 5}}
+
+		m := mock.NewMockService(ctrl)
+		r := resolver.NewUser(m)
+
+		m.EXPECT().
+			FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 2, Cursor: iface.EncodeCursor(3)}).
+			Return(users, iface.EncodeCursor(5), nil)
+
+		conn, err := r.Users(ctxDebug, resolver.Page{First: intp(2), After: strp(iface.EncodeCursor(3))}, false, nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, len(conn.Nodes), 2)
+		assert.True(t, conn.PageInfo.HasNextPage)
+		assert.True(t, conn.PageInfo.HasPreviousPage)
+		assert.Equal(t, iface.EncodeCursor(4), *conn.PageInfo.StartCursor)
+		assert.Equal(t, iface.EncodeCursor(5), *conn.PageInfo.EndCursor)
+	}
+
+	// pages backward
+	{
+		users := []*entity.User{{ID: 4}, {ID: 5}}
+
+		m := mock.NewMockService(ctrl)
+		r := resolver.NewUser(m)
+
+		m.EXPECT().
+			FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 2, Cursor: iface.EncodeCursor(6), Backward: true}).
+			Return(users, iface.EncodeCursor(4), nil)
+
+		conn, err := r.Users(ctxDebug, resolver.Page{Last: intp(2), Before: strp(iface.EncodeCursor(6))}, false, nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, gentity.NewID(gentity.UserTypename, 4), conn.Nodes[0].ID)
+		assert.True(t, conn.PageInfo.HasPreviousPage)
+		assert.True(t, conn.PageInfo.HasNextPage)
+	}
+
+	// empty page has no cursors
+	{
+		m := mock.NewMockService(ctrl)
+		r := resolver.NewUser(m)
+
+		m.EXPECT().
+			FilterUsers(gomock.Any(), iface.FilterUsers{}).
+			Return([]*entity.User{}, "", nil)
+
+		users, err := r.Users(ctxDebug, resolver.Page{}, false, nil, nil)
+		assert.Nil(t, err)
+		assert.Len(t, users.Nodes, 0)
+		assert.False(t, users.PageInfo.HasNextPage)
+		assert.Nil(t, users.PageInfo.StartCursor)
+		assert.Nil(t, users.PageInfo.EndCursor)
+	}
+
+	// succeed filtered and ordered
+	{
+		m := mock.NewMockService(ctrl)
+		r := resolver.NewUser(m)
+
+		m.EXPECT().
+			FilterUsers(gomock.Any(), iface.FilterUsers{
+				Email:         "a@b.c",
+				EmailDomain:   "b.c",
+				NamePrefix:    "Jo",
+				CreatedFrom:   time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC),
+				OrderBy:       iface.UserOrderCreated,
+				Desc:          true,
+				Limit:         2,
+			}).
+			Return([]*entity.User{}, "", nil)
+
+		desc := gentity.OrderDirectionDesc
+		_, err := r.Users(ctxDebug, resolver.Page{First: intp(2)}, false, &gentity.UserFilter{
+			Email:       strp("a@b.c"),
+			EmailDomain: strp("b.c"),
+			NamePrefix:  strp("Jo"),
+			CreatedFrom: timep(time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)),
+			CreatedTo:   timep(time.Date(2019, 7, 31, 0, 0, 0, 0, time.UTC)),
+		}, &gentity.UserOrder{Field: gentity.UserOrderFieldCreated, Direction: &desc})
+		assert.Nil(t, err)
+	}
+
+	// succeed with an empty page for first or last 0, without reading it
+	{
+		r := resolver.NewUser(mock.NewMockService(ctrl))
+
+		conn, err := r.Users(ctxDebug, resolver.Page{First: intp(0)}, false, nil, nil)
+		assert.Nil(t, err)
+		assert.Len(t, conn.Nodes, 0)
+		assert.Len(t, conn.Edges, 0)
+		assert.False(t, conn.PageInfo.HasNextPage)
+
+		conn, err = r.Users(ctxDebug, resolver.Page{Last: intp(0)}, false, nil, nil)
+		assert.Nil(t, err)
+		assert.Len(t, conn.Nodes, 0)
+	}
+
+	// fails if first and last are combined or negative
+	{
+		r := resolver.NewUser(mock.NewMockService(ctrl))
+
+		_, err := r.Users(ctxDebug, resolver.Page{First: intp(2), Last: intp(2)}, false, nil, nil)
+		assert.Equal(t, resolver.ErrInvalidPage, err)
+
+		_, err = r.Users(ctxDebug, resolver.Page{After: strp(""), Before: strp("")}, false, nil, nil)
+		assert.Equal(t, resolver.ErrInvalidPage, err)
+
+		_, err = r.Users(ctxDebug, resolver.Page{First: intp(-1)}, false, nil, nil)
+		assert.Equal(t, resolver.ErrInvalidPage, err)
+
+		_, err = r.Users(ctxDebug, resolver.Page{Last: intp(-1)}, false, nil, nil)
+		assert.Equal(t, resolver.ErrInvalidPage, err)
 	}
 
 	// fails if service fails
@@ -98,16 +235,71 @@ func TestUserUsers(t *testing.T) {
 		r := resolver.NewUser(m)
 
 		m.EXPECT().
-			FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 4}).
-			Return(nil, fmt.Errorf("opz"))
+			FilterUsers(gomock.Any(), iface.FilterUsers{Limit: 4, IncludeDeleted: true}).
+			Return(nil, "", fmt.Errorf("opz"))
 
-		users, err := r.Users(ctxDebug, 4)
+		users, err := r.Users(ctxDebug, resolver.Page{First: intp(4)}, true, nil, nil)
 		assert.Nil(t, users)
 		assert.NotNil(t, err)
 		assert.Equal(t, err.Error(), "opz")
 	}
 }
 
+func TestUsersPage(t *testing.T) {
+	// succeed with the default size without first, last or limit
+	{
+		page := resolver.UsersPage(nil, nil, nil, nil, nil)
+		assert.Equal(t, resolver.Page{First: intp(resolver.UsersDefaultSize)}, page)
+
+		page = resolver.UsersPage(nil, nil, nil, strp("b"), nil)
+		assert.Equal(t, resolver.Page{Last: intp(resolver.UsersDefaultSize), Before: strp("b")}, page)
+	}
+
+	// succeed with limit as first
+	{
+		page := resolver.UsersPage(nil, strp("a"), nil, nil, intp(3))
+		assert.Equal(t, resolver.Page{First: intp(3), After: strp("a")}, page)
+	}
+
+	// succeed preferring first to limit
+	{
+		page := resolver.UsersPage(intp(2), nil, nil, nil, intp(3))
+		assert.Equal(t, resolver.Page{First: intp(2)}, page)
+	}
+
+	// succeed with last alone
+	{
+		page := resolver.UsersPage(nil, nil, intp(2), nil, nil)
+		assert.Equal(t, resolver.Page{Last: intp(2)}, page)
+	}
+}
+
+func TestUserTotalCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := iface.FilterUsers{Limit: 2, IncludeDeleted: true}
+
+	// succeed
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().CountUsers(gomock.Any(), filter).Return(int64(7), nil)
+
+		n, err := resolver.NewUser(m).TotalCount(ctxDebug, &gentity.UserConnection{Filter: filter})
+		assert.Nil(t, err)
+		assert.Equal(t, 7, n)
+	}
+
+	// fails if service fails
+	{
+		m := mock.NewMockService(ctrl)
+		m.EXPECT().CountUsers(gomock.Any(), filter).Return(int64(0), fmt.Errorf("opz"))
+
+		_, err := resolver.NewUser(m).TotalCount(ctxDebug, &gentity.UserConnection{Filter: filter})
+		assert.Equal(t, err.Error(), "opz")
+	}
+}
+
 func TestUserEmails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -120,13 +312,34 @@ func TestUserEmails(t *testing.T) {
 		r := resolver.NewUser(m)
 
 		m.EXPECT().
-			FilterEmails(gomock.Any(), iface.FilterEmails{UserID: user.ID}).
-			Return([]*entity.Email{user}, nil)
+			FilterEmailsByUserIDs(gomock.Any(), iface.FilterEmails{}, []int64{user.ID}).
+			Return(map[int64]*iface.EmailsPage{user.ID: {Emails: []*entity.Email{user}}}, nil)
 
-		emails, err := r.Emails(ctxDebug, &gentity.User{ID: strconv.FormatInt(user.ID, 10)})
+		emails, err := r.Emails(ctxDebug, &gentity.User{ID: gentity.NewID(gentity.UserTypename, user.ID)}, nil, nil, nil, nil, nil)
 		assert.Nil(t, err)
 		assert.NotNil(t, emails)
-		assert.Equal(t, len(emails), 1)
+		assert.Equal(t, len(emails.Nodes), 1)
+		assert.Equal(t, len(emails.Edges), 1)
+		assert.Equal(t, iface.EncodeCursor(user.ID), emails.Edges[0].Cursor)
+	}
+
+	// pages with first and after
+	{
+		email := &entity.Email{ID: 4, Address: "a@b.c"}
+		after := iface.EncodeCursor(3)
+
+		m := mock.NewMockService(ctrl)
+		r := resolver.NewUser(m)
+
+		m.EXPECT().
+			FilterEmailsByUserIDs(gomock.Any(), iface.FilterEmails{Limit: 1, Cursor: after}, []int64{2}).
+			Return(map[int64]*iface.EmailsPage{2: {Emails: []*entity.Email{email}, Next: iface.EncodeCursor(email.ID)}}, nil)
+
+		emails, err := r.Emails(ctxDebug, &gentity.User{ID: gentity.NewID(gentity.UserTypename, 2)}, intp(1), &after, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, len(emails.Nodes), 1)
+		assert.True(t, emails.PageInfo.HasNextPage)
+		assert.Equal(t, iface.FilterEmails{UserID: 2, Limit: 1, Cursor: after}, emails.Filter)
 	}
 
 	// fail if invalid ID
@@ -134,23 +347,88 @@ func TestUserEmails(t *testing.T) {
 		m := mock.NewMockService(ctrl)
 		r := resolver.NewUser(m)
 
-		emails, err := r.Emails(ctxDebug, &gentity.User{ID: "0"})
+		emails, err := r.Emails(ctxDebug, &gentity.User{ID: "0"}, nil, nil, nil, nil, nil)
 		assert.Nil(t, emails)
 		assert.Equal(t, err, iface.ErrInvalidID)
 	}
 
+	// fail if invalid page
+	{
+		r := resolver.NewUser(mock.NewMockService(ctrl))
+
+		emails, err := r.Emails(ctxDebug, &gentity.User{ID: gentity.NewID(gentity.UserTypename, 2)}, intp(1), nil, intp(1), nil, nil)
+		assert.Nil(t, emails)
+		assert.Equal(t, err, resolver.ErrInvalidPage)
+	}
+
 	// fails if service fails
 	{
 		m := mock.NewMockService(ctrl)
 		r := resolver.NewUser(m)
 
 		m.EXPECT().
-			FilterEmails(gomock.Any(), iface.FilterEmails{UserID: 2}).
+			FilterEmailsByUserIDs(gomock.Any(), iface.FilterEmails{}, []int64{2}).
 			Return(nil, fmt.Errorf("opz"))
 
-		users, err := r.Emails(ctxDebug, &gentity.User{ID: strconv.FormatInt(2, 10)})
+		users, err := r.Emails(ctxDebug, &gentity.User{ID: gentity.NewID(gentity.UserTypename, 2)}, nil, nil, nil, nil, nil)
 		assert.Nil(t, users)
 		assert.NotNil(t, err)
 		assert.Equal(t, err.Error(), "opz")
 	}
 }
+
+func TestUserWithStorage(t *testing.T) {
+	srv := service.New(memory.New())
+	r := resolver.NewUser(srv)
+
+	userID, err := srv.AddUser(ctxDebug, "John Doe")
+	assert.Nil(t, err)
+	_, err = srv.AddEmail(ctxDebug, userID, "john@example.com")
+	assert.Nil(t, err)
+
+	users, err := r.Users(ctxDebug, resolver.Page{First: intp(10)}, false, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, users.Nodes, 1)
+	assert.Equal(t, gentity.NewID(gentity.UserTypename, userID), users.Nodes[0].ID)
+	assert.Equal(t, "John Doe", users.Nodes[0].Name)
+
+	emails, err := r.Emails(ctxDebug, users.Nodes[0], nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, emails.Nodes, 1)
+	assert.Equal(t, "john@example.com", emails.Nodes[0].Address)
+
+	u, err := resolver.NewEmail(srv).User(ctxDebug, emails.Nodes[0])
+	assert.Nil(t, err)
+	assert.Equal(t, users.Nodes[0].ID, u.ID)
+
+	// fails if cursor is invalid
+	_, err = r.Users(ctxDebug, resolver.Page{After: strp("opz")}, false, nil, nil)
+	assert.Equal(t, iface.ErrInvalidCursor, err)
+
+	// pages backward from the end, then forward again
+	for _, name := range []string{"Jane", "Jack"} {
+		_, err := srv.AddUser(ctxDebug, name)
+		assert.Nil(t, err)
+	}
+
+	last, err := r.Users(ctxDebug, resolver.Page{Last: intp(2)}, false, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, last.Nodes, 2)
+	assert.Equal(t, "Jane", last.Nodes[0].Name)
+	assert.Equal(t, "Jack", last.Nodes[1].Name)
+	assert.True(t, last.PageInfo.HasPreviousPage)
+
+	first, err := r.Users(ctxDebug, resolver.Page{Last: intp(2), Before: last.PageInfo.StartCursor}, false, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, first.Nodes, 1)
+	assert.Equal(t, "John Doe", first.Nodes[0].Name)
+	assert.False(t, first.PageInfo.HasPreviousPage)
+
+	rest, err := r.Users(ctxDebug, resolver.Page{First: intp(5), After: first.PageInfo.EndCursor}, false, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, last.Nodes, rest.Nodes)
+
+	n, err := r.TotalCount(ctxDebug, rest)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+}
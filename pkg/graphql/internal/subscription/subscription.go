@@ -0,0 +1,104 @@
+package subscription
+
+import (
+	"context"
+
+	"github.com/rafaelsq/boiler/pkg/event"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/log"
+	"github.com/rafaelsq/errors"
+)
+
+func NewSubscription(service iface.Service) *Subscription {
+	return &Subscription{
+		service: service,
+	}
+}
+
+// Subscription streams the changes the service commits while the client is subscribed;
+// a change whose object can no longer be loaded is skipped.
+type Subscription struct {
+	service iface.Service
+}
+
+func (s *Subscription) UserAdded(ctx context.Context) (<-chan *entity.User, error) {
+	events := s.service.Subscribe(ctx, event.UserAdded)
+	users := make(chan *entity.User)
+
+	go func() {
+		defer close(users)
+
+		for e := range events {
+			u, err := s.service.GetUserByID(ctx, e.UserID)
+			if err != nil {
+				log.Log(errors.New("fail to get added user").SetArg("userID", e.UserID).SetParent(err))
+				continue
+			}
+
+			select {
+			case users <- entity.NewUser(u):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return users, nil
+}
+
+func (s *Subscription) UserDeleted(ctx context.Context) (<-chan string, error) {
+	return s.ids(ctx, event.UserDeleted, func(e event.Event) string { return entity.NewID(entity.UserTypename, e.UserID) }), nil
+}
+
+func (s *Subscription) EmailAdded(ctx context.Context) (<-chan *entity.Email, error) {
+	events := s.service.Subscribe(ctx, event.EmailAdded)
+	emails := make(chan *entity.Email)
+
+	go func() {
+		defer close(emails)
+
+		for e := range events {
+			es, _, err := s.service.FilterEmails(ctx, iface.FilterEmails{EmailID: e.EmailID})
+			if err == nil && len(es) == 0 {
+				err = iface.ErrNotFound
+			}
+			if err != nil {
+				log.Log(errors.New("fail to get added email").SetArg("emailID", e.EmailID).SetParent(err))
+				continue
+			}
+
+			select {
+			case emails <- entity.NewEmail(es[0]):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return emails, nil
+}
+
+func (s *Subscription) EmailDeleted(ctx context.Context) (<-chan string, error) {
+	return s.ids(ctx, event.EmailDeleted, func(e event.Event) string { return entity.NewID(entity.EmailTypename, e.EmailID) }), nil
+}
+
+// ids streams the ID that ID picks from each event of kind.
+func (s *Subscription) ids(ctx context.Context, kind event.Kind, ID func(event.Event) string) <-chan string {
+	events := s.service.Subscribe(ctx, kind)
+	IDs := make(chan string)
+
+	go func() {
+		defer close(IDs)
+
+		for e := range events {
+			select {
+			case IDs <- ID(e):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return IDs
+}
@@ -0,0 +1,127 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	pentity "github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/event"
+	"github.com/rafaelsq/boiler/pkg/graphql/internal/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// events returns a channel holding es, closed after them.
+func events(es ...event.Event) <-chan event.Event {
+	ch := make(chan event.Event, len(es))
+	for _, e := range es {
+		ch <- e
+	}
+	close(ch)
+
+	return ch
+}
+
+func TestUserAdded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock.NewMockService(ctrl)
+	s := NewSubscription(service)
+
+	ctx := context.TODO()
+
+	// streams the added users, skipping those it cannot load
+	service.EXPECT().Subscribe(ctx, event.UserAdded).Return(events(
+		event.Event{Kind: event.UserAdded, UserID: 1},
+		event.Event{Kind: event.UserAdded, UserID: 2},
+	))
+	service.EXPECT().GetUserByID(ctx, int64(1)).Return(nil, fmt.Errorf("opz"))
+	service.EXPECT().GetUserByID(ctx, int64(2)).Return(&pentity.User{ID: 2, Name: "name", Version: 1}, nil)
+
+	users, err := s.UserAdded(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, &entity.User{ID: entity.NewID(entity.UserTypename, 2), Name: "name", Version: 1}, <-users)
+
+	_, open := <-users
+	assert.False(t, open)
+}
+
+func TestUserDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock.NewMockService(ctrl)
+	s := NewSubscription(service)
+
+	ctx := context.TODO()
+
+	service.EXPECT().Subscribe(ctx, event.UserDeleted).Return(events(
+		event.Event{Kind: event.UserDeleted, UserID: 3},
+	))
+
+	IDs, err := s.UserDeleted(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.NewID(entity.UserTypename, 3), <-IDs)
+
+	_, open := <-IDs
+	assert.False(t, open)
+}
+
+func TestEmailAdded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock.NewMockService(ctrl)
+	s := NewSubscription(service)
+
+	ctx := context.TODO()
+
+	// streams the added emails, skipping those it cannot load
+	service.EXPECT().Subscribe(ctx, event.EmailAdded).Return(events(
+		event.Event{Kind: event.EmailAdded, UserID: 1, EmailID: 4},
+		event.Event{Kind: event.EmailAdded, UserID: 1, EmailID: 5},
+	))
+	service.EXPECT().FilterEmails(ctx, iface.FilterEmails{EmailID: 4}).Return([]*pentity.Email{}, "", nil)
+	service.EXPECT().
+		FilterEmails(ctx, iface.FilterEmails{EmailID: 5}).
+		Return([]*pentity.Email{{ID: 5, UserID: 1, Address: "a@b.c"}}, "", nil)
+
+	emails, err := s.EmailAdded(ctx)
+	assert.Nil(t, err)
+
+	e := <-emails
+	assert.Equal(t, entity.NewID(entity.EmailTypename, 5), e.ID)
+	assert.Equal(t, "a@b.c", e.Address)
+	assert.Equal(t, entity.NewID(entity.UserTypename, 1), e.User.ID)
+
+	_, open := <-emails
+	assert.False(t, open)
+}
+
+func TestEmailDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock.NewMockService(ctrl)
+	s := NewSubscription(service)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// stops sending once ctx is done, as the bus closes the events then
+	ch := make(chan event.Event, 1)
+	service.EXPECT().Subscribe(ctx, event.EmailDeleted).Return(ch)
+
+	IDs, err := s.EmailDeleted(ctx)
+	assert.Nil(t, err)
+
+	cancel()
+	ch <- event.Event{Kind: event.EmailDeleted, EmailID: 6}
+	close(ch)
+
+	for range IDs {
+	}
+}
@@ -0,0 +1,125 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/99designs/gqlgen/handler"
+	"github.com/bradfitz/gomemcache/memcache"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rafaelsq/boiler/pkg/cache"
+	"github.com/rafaelsq/boiler/pkg/log"
+	"github.com/rafaelsq/errors"
+)
+
+// queryExpiration is how long memcache keeps a persisted query document.
+const queryExpiration = 24 * time.Hour
+
+// QueryCache stores the documents of automatic persisted queries by their sha256 hash.
+type QueryCache = handler.PersistedQueryCache
+
+// QueryHash is the hash clients send for query.
+func QueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewLRUQueries(size int) (*LRUQueries, error) {
+	c, err := lru.New(size)
+	if err != nil {
+		return nil, errors.New("could not create query cache").SetArg("size", size).SetParent(err)
+	}
+
+	return &LRUQueries{c}, nil
+}
+
+// LRUQueries keeps the most recently used documents in memory.
+type LRUQueries struct {
+	cache *lru.Cache
+}
+
+func (q *LRUQueries) Add(ctx context.Context, hash string, query string) {
+	q.cache.Add(hash, query)
+}
+
+func (q *LRUQueries) Get(ctx context.Context, hash string) (string, bool) {
+	query, ok := q.cache.Get(hash)
+	if !ok {
+		return "", false
+	}
+
+	return query.(string), true
+}
+
+func NewMemcacheQueries(client cache.Client, fallback QueryCache) *MemcacheQueries {
+	return &MemcacheQueries{client, fallback}
+}
+
+// MemcacheQueries shares the documents between servers through memcache; it also
+// keeps them in fallback, which answers when memcache misses or fails.
+type MemcacheQueries struct {
+	client   cache.Client
+	fallback QueryCache
+}
+
+func queryCacheKey(hash string) string {
+	return "apq-" + hash
+}
+
+func (q *MemcacheQueries) Add(ctx context.Context, hash string, query string) {
+	q.fallback.Add(ctx, hash, query)
+
+	err := q.client.Set(&memcache.Item{
+		Key:        queryCacheKey(hash),
+		Value:      []byte(query),
+		Expiration: int32(queryExpiration.Seconds()),
+	})
+	if err != nil {
+		log.Log(errors.New("could not cache query").SetArg("hash", hash).SetParent(err))
+	}
+}
+
+func (q *MemcacheQueries) Get(ctx context.Context, hash string) (string, bool) {
+	key := queryCacheKey(hash)
+
+	items, err := q.client.GetMulti([]string{key})
+	if err != nil {
+		log.Log(errors.New("could not get cached query").SetArg("hash", hash).SetParent(err))
+	}
+
+	if item, ok := items[key]; ok {
+		return string(item.Value), true
+	}
+
+	return q.fallback.Get(ctx, hash)
+}
+
+func NewAllowList(documents ...string) *AllowList {
+	queries := make(map[string]string, len(documents))
+	for _, query := range documents {
+		queries[QueryHash(query)] = query
+	}
+
+	return &AllowList{queries}
+}
+
+// AllowList holds the only documents QueryHandleFunc runs when given as its cache;
+// clients may send them in full or by hash, but cannot register others.
+type AllowList struct {
+	queries map[string]string
+}
+
+func (a *AllowList) Add(ctx context.Context, hash string, query string) {}
+
+func (a *AllowList) Get(ctx context.Context, hash string) (string, bool) {
+	query, ok := a.queries[hash]
+	return query, ok
+}
+
+// Allows tells whether query is one of the registered documents.
+func (a *AllowList) Allows(query string) bool {
+	_, ok := a.queries[QueryHash(query)]
+	return ok
+}
@@ -0,0 +1,128 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rafaelsq/boiler/pkg/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// client is an in-process cache.Client.
+type client struct {
+	items map[string][]byte
+	err   error
+}
+
+func (c *client) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	items := map[string]*memcache.Item{}
+	for _, key := range keys {
+		if value, has := c.items[key]; has {
+			items[key] = &memcache.Item{Key: key, Value: value}
+		}
+	}
+
+	return items, nil
+}
+
+func (c *client) Set(item *memcache.Item) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	c.items[item.Key] = item.Value
+	return nil
+}
+
+func (c *client) Add(item *memcache.Item) error {
+	if _, has := c.items[item.Key]; has {
+		return memcache.ErrNotStored
+	}
+
+	return c.Set(item)
+}
+
+func (c *client) Delete(key string) error {
+	delete(c.items, key)
+	return c.err
+}
+
+func TestLRUQueries(t *testing.T) {
+	ctx := context.Background()
+
+	queries, err := graphql.NewLRUQueries(1)
+	require.Nil(t, err)
+
+	// succeed
+	{
+		queries.Add(ctx, "a", "{ a }")
+		query, ok := queries.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, "{ a }", query)
+	}
+
+	// evicts the least recently used
+	{
+		queries.Add(ctx, "b", "{ b }")
+		_, ok := queries.Get(ctx, "a")
+		assert.False(t, ok)
+	}
+
+	// fails if size is not positive
+	{
+		_, err := graphql.NewLRUQueries(0)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestMemcacheQueries(t *testing.T) {
+	ctx := context.Background()
+	q := "{ users { nodes { id } } }"
+	hash := graphql.QueryHash(q)
+
+	// succeed sharing the query through memcache
+	{
+		c := &client{items: map[string][]byte{}}
+		fallback, err := graphql.NewLRUQueries(10)
+		require.Nil(t, err)
+
+		graphql.NewMemcacheQueries(c, fallback).Add(ctx, hash, q)
+
+		other, err := graphql.NewLRUQueries(10)
+		require.Nil(t, err)
+
+		query, ok := graphql.NewMemcacheQueries(c, other).Get(ctx, hash)
+		assert.True(t, ok)
+		assert.Equal(t, q, query)
+	}
+
+	// falls back when memcache fails
+	{
+		c := &client{items: map[string][]byte{}, err: fmt.Errorf("opz")}
+		fallback, err := graphql.NewLRUQueries(10)
+		require.Nil(t, err)
+
+		queries := graphql.NewMemcacheQueries(c, fallback)
+		queries.Add(ctx, hash, q)
+
+		query, ok := queries.Get(ctx, hash)
+		assert.True(t, ok)
+		assert.Equal(t, q, query)
+	}
+
+	// fails if neither has the query
+	{
+		fallback, err := graphql.NewLRUQueries(10)
+		require.Nil(t, err)
+
+		_, ok := graphql.NewMemcacheQueries(&client{items: map[string][]byte{}}, fallback).Get(ctx, hash)
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,33 @@
+package iface
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+const cursorPrefix = "id:"
+
+// EncodeCursor returns the opaque cursor of a page that ends at the given ID.
+func EncodeCursor(ID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(ID, 10)))
+}
+
+// DecodeCursor returns the ID a cursor points after; the empty cursor is the first page.
+func DecodeCursor(cursor string) (int64, error) {
+	if len(cursor) == 0 {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, ErrInvalidCursor
+	}
+
+	ID, err := strconv.ParseInt(strings.TrimPrefix(string(raw), cursorPrefix), 10, 64)
+	if err != nil || ID < 1 {
+		return 0, ErrInvalidCursor
+	}
+
+	return ID, nil
+}
@@ -8,4 +8,6 @@ var (
 	ErrNotFound      = errors.New("not found").SetArg("code", "e0")
 	ErrAlreadyExists = errors.New("already exists").SetArg("code", "s1")
 	ErrInvalidID     = errors.New("invalid ID").SetArg("code", "iid")
+	ErrConflict      = errors.New("version conflict").SetArg("code", "c0")
+	ErrInvalidCursor = errors.New("invalid cursor").SetArg("code", "icur")
 )
@@ -1,19 +1,64 @@
 package iface
 
+import (
+	"time"
+
+	"github.com/rafaelsq/boiler/pkg/entity"
+)
+
 const (
 	FilterUsersDefaultLimit  uint = 50
 	FilterEmailsDefaultLimit uint = 50
 )
 
+// UserOrder is the field users are listed by; ties are listed in ID order.
+type UserOrder string
+
+const (
+	UserOrderID      UserOrder = ""
+	UserOrderName    UserOrder = "name"
+	UserOrderCreated UserOrder = "created"
+)
+
 type FilterUsers struct {
-	Email  string
-	Offset uint
-	Limit  uint
+	// Email matches users with that address; EmailDomain, with any address at that domain
+	Email       string
+	EmailDomain string
+	NamePrefix  string
+
+	// CreatedFrom and CreatedBefore bound the creation time, the latter exclusive; zero is unbounded
+	CreatedFrom   time.Time
+	CreatedBefore time.Time
+
+	OrderBy UserOrder
+	Desc    bool
+	Limit   uint
+
+	// Cursor resumes the page that returned it; Backward pages toward the start of the order,
+	// from the last match without a Cursor, and still lists in the given order.
+	Cursor   string
+	Backward bool
+
+	// IncludeDeleted also matches soft deleted users
+	IncludeDeleted bool
 }
 
 type FilterEmails struct {
 	EmailID int64
 	UserID  int64
-	Offset  uint
 	Limit   uint
+
+	// Cursor resumes the page that returned it; Backward pages toward lower IDs,
+	// from the last match without a Cursor, and still lists in ID order.
+	Cursor   string
+	Backward bool
+
+	// IncludeDeleted also matches deleted emails and the emails of deleted users
+	IncludeDeleted bool
+}
+
+// EmailsPage is a page of a user's emails and the cursor of the following one.
+type EmailsPage struct {
+	Emails []*entity.Email
+	Next   string
 }
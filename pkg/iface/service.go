@@ -5,18 +5,28 @@ import (
 	"context"
 
 	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/event"
 )
 
 type Service interface {
 	// user
 	AddUser(context.Context, string) (int64, error)
-	DeleteUser(context.Context, int64) error
-	FilterUsers(context.Context, FilterUsers) ([]*entity.User, error)
+	UpdateUser(context.Context, int64, int64, string) error
+	DeleteUser(context.Context, int64, int64) error
+	RestoreUser(context.Context, int64, int64) error
+	FilterUsers(context.Context, FilterUsers) ([]*entity.User, string, error)
+	CountUsers(context.Context, FilterUsers) (int64, error)
 	GetUserByID(context.Context, int64) (*entity.User, error)
+	GetUsersByID(context.Context, ...int64) ([]*entity.User, error)
 	GetUserByEmail(context.Context, string) (*entity.User, error)
 
 	// email
-	FilterEmails(context.Context, FilterEmails) ([]*entity.Email, error)
+	FilterEmails(context.Context, FilterEmails) ([]*entity.Email, string, error)
+	CountEmails(context.Context, FilterEmails) (int64, error)
+	FilterEmailsByUserIDs(context.Context, FilterEmails, []int64) (map[int64]*EmailsPage, error)
 	AddEmail(context.Context, int64, string) (int64, error)
 	DeleteEmail(context.Context, int64) error
+
+	// events
+	Subscribe(context.Context, event.Kind) <-chan event.Event
 }
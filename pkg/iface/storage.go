@@ -3,24 +3,37 @@ package iface
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/rafaelsq/boiler/pkg/entity"
 )
 
+// Tx is a storage transaction; *sql.Tx satisfies it.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// Storage filters page in ID order and return the cursor of the following page, empty on the last one;
+// counts ignore the page.
 type Storage interface {
 	// begin transaction
-	Tx() (*sql.Tx, error)
+	Tx() (Tx, error)
 
-	// user
-	AddUser(ctx context.Context, tx *sql.Tx, name string) (int64, error)
-	DeleteUser(ctx context.Context, tx *sql.Tx, userID int64) error
-	FilterUsersID(ctx context.Context, filter FilterUsers) ([]int64, error)
-	FetchUsers(ctx context.Context, ID ...int64) ([]*entity.User, error)
+	// user; writes on a user not at the given version fail with ErrConflict.
+	// Deletes are soft: a deleted user and its emails are hidden until RestoreUser.
+	AddUser(ctx context.Context, tx Tx, name string) (int64, error)
+	UpdateUser(ctx context.Context, tx Tx, userID, version int64, name string) error
+	DeleteUser(ctx context.Context, tx Tx, userID, version int64) error
+	RestoreUser(ctx context.Context, tx Tx, userID, version int64) error
+	FilterUsersID(ctx context.Context, filter FilterUsers) ([]int64, string, error)
+	CountUsers(ctx context.Context, filter FilterUsers) (int64, error)
+	FetchUsers(ctx context.Context, includeDeleted bool, ID ...int64) ([]*entity.User, error)
 
 	// email
-	AddEmail(ctx context.Context, tx *sql.Tx, userID int64, address string) (int64, error)
-	DeleteEmail(ctx context.Context, tx *sql.Tx, emailID int64) error
-	DeleteEmailsByUserID(ctx context.Context, tx *sql.Tx, userID int64) error
-	FilterEmails(ctx context.Context, filter FilterEmails) ([]*entity.Email, error)
+	AddEmail(ctx context.Context, tx Tx, userID int64, address string) (int64, error)
+	DeleteEmail(ctx context.Context, tx Tx, emailID int64) error
+	DeleteEmailsByUserID(ctx context.Context, tx Tx, userID int64) error
+	FilterEmails(ctx context.Context, filter FilterEmails) ([]*entity.Email, string, error)
+	CountEmails(ctx context.Context, filter FilterEmails) (int64, error)
+	FilterEmailsByUserIDs(ctx context.Context, filter FilterEmails, userIDs []int64) (map[int64]*EmailsPage, error)
 }
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rafaelsq/errors"
+)
+
+// lockName is the MySQL named lock held while migrating.
+const lockName = "boiler.schema_migrations"
+
+type dialect struct {
+	lock   func(ctx context.Context, conn *sql.Conn) error
+	unlock func(ctx context.Context, conn *sql.Conn, err error) error
+}
+
+var dialects = map[string]dialect{
+	// MySQL DDL is not transactional; a named lock serializes migrators and
+	// is released by the server if the connection dies.
+	MySQL: {
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			var got sql.NullInt64
+			err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 10)", lockName).Scan(&got)
+			if err != nil {
+				return errors.New("could not lock").SetParent(err)
+			}
+
+			if got.Int64 != 1 {
+				return ErrLocked
+			}
+
+			return nil
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn, _ error) error {
+			_, err := conn.ExecContext(ctx, "DO RELEASE_LOCK(?)", lockName)
+			return err
+		},
+	},
+	// SQLite takes the database write lock for the whole run, so a failed
+	// run is rolled back and never leaves the schema dirty.
+	SQLite: {
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+				return errors.New("could not lock").SetParent(err)
+			}
+
+			return nil
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn, err error) error {
+			if err != nil {
+				_, er := conn.ExecContext(ctx, "ROLLBACK")
+				return er
+			}
+
+			_, er := conn.ExecContext(ctx, "COMMIT")
+			return er
+		},
+	},
+}
@@ -0,0 +1,348 @@
+// Package migration applies the numbered SQL migrations embedded in the binary
+// and records them in the schema_migrations table.
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rafaelsq/errors"
+)
+
+const (
+	MySQL  = "mysql"
+	SQLite = "sqlite"
+)
+
+var (
+	ErrDirty          = errors.New("database is dirty; fix it by hand and use force")
+	ErrLocked         = errors.New("another migration is running")
+	ErrUnknownVersion = errors.New("unknown migration version")
+	ErrUnknownDialect = errors.New("unknown dialect")
+)
+
+//go:embed mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// files are named like 0001_init.up.sql and 0001_init.down.sql
+var rxFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// statements are split at semicolons ending a line
+var rxStatement = regexp.MustCompile(`;\s*(\n|$)`)
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version BIGINT NOT NULL PRIMARY KEY,
+  dirty BOOLEAN NOT NULL,
+  applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// State is a migration and whether it is applied.
+type State struct {
+	*Migration
+	Applied bool
+	Dirty   bool
+}
+
+// Load returns the migrations of the dialect sorted by version.
+func Load(dialect string) ([]*Migration, error) {
+	if _, has := dialects[dialect]; !has {
+		return nil, ErrUnknownDialect
+	}
+
+	entries, err := fs.ReadDir(files, dialect)
+	if err != nil {
+		return nil, errors.New("could not read migrations").SetParent(err)
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		ms := rxFile.FindStringSubmatch(entry.Name())
+		if ms == nil {
+			return nil, errors.New("invalid migration file name").SetArg("file", entry.Name())
+		}
+
+		version, err := strconv.ParseUint(ms[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, errors.New("invalid migration version").SetArg("file", entry.Name())
+		}
+
+		body, err := files.ReadFile(dialect + "/" + entry.Name())
+		if err != nil {
+			return nil, errors.New("could not read migration").SetArg("file", entry.Name()).SetParent(err)
+		}
+
+		m, has := byVersion[uint(version)]
+		if !has {
+			m = &Migration{Version: uint(version), Name: ms[2]}
+			byVersion[m.Version] = m
+		} else if m.Name != ms[2] {
+			return nil, errors.New("duplicated migration version").SetArg("file", entry.Name())
+		}
+
+		if ms[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if len(m.Up) == 0 || len(m.Down) == 0 {
+			return nil, errors.New("migration must have up and down files").SetArg("version", m.Version)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []*Migration
+}
+
+func New(db *sql.DB, dialect string) (*Migrator, error) {
+	migrations, err := Load(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    dialects[dialect],
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(conn *sql.Conn, applied map[uint]bool) error {
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			if _, has := applied[mg.Version]; has {
+				continue
+			}
+
+			if _, err := conn.ExecContext(ctx,
+				"INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)", mg.Version, true,
+			); err != nil {
+				return errors.New("could not register migration").SetArg("version", mg.Version).SetParent(err)
+			}
+
+			if err := exec(ctx, conn, mg.Up); err != nil {
+				return errors.New("could not apply migration").SetArg("version", mg.Version).SetParent(err)
+			}
+
+			if _, err := conn.ExecContext(ctx,
+				"UPDATE schema_migrations SET dirty = ? WHERE version = ?", false, mg.Version,
+			); err != nil {
+				return errors.New("could not register migration").SetArg("version", mg.Version).SetParent(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Down reverts the last n applied migrations.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.run(ctx, func(conn *sql.Conn, applied map[uint]bool) error {
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+
+		versions := make([]uint, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		if n < len(versions) {
+			versions = versions[:n]
+		}
+
+		for _, version := range versions {
+			mg := m.find(version)
+			if mg == nil {
+				return ErrUnknownVersion
+			}
+
+			if _, err := conn.ExecContext(ctx,
+				"UPDATE schema_migrations SET dirty = ? WHERE version = ?", true, version,
+			); err != nil {
+				return errors.New("could not register migration").SetArg("version", version).SetParent(err)
+			}
+
+			if err := exec(ctx, conn, mg.Down); err != nil {
+				return errors.New("could not revert migration").SetArg("version", version).SetParent(err)
+			}
+
+			if _, err := conn.ExecContext(ctx,
+				"DELETE FROM schema_migrations WHERE version = ?", version,
+			); err != nil {
+				return errors.New("could not unregister migration").SetArg("version", version).SetParent(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Force records the schema as clean at version without running any migration;
+// version 0 means no migration applied.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	if version != 0 && m.find(version) == nil {
+		return ErrUnknownVersion
+	}
+
+	return m.run(ctx, func(conn *sql.Conn, applied map[uint]bool) error {
+		if _, err := conn.ExecContext(ctx,
+			"DELETE FROM schema_migrations WHERE version > ?", version,
+		); err != nil {
+			return errors.New("could not force version").SetParent(err)
+		}
+
+		if _, err := conn.ExecContext(ctx,
+			"UPDATE schema_migrations SET dirty = ?", false,
+		); err != nil {
+			return errors.New("could not force version").SetParent(err)
+		}
+
+		for _, mg := range m.migrations {
+			if _, has := applied[mg.Version]; has || mg.Version > version {
+				continue
+			}
+
+			if _, err := conn.ExecContext(ctx,
+				"INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)", mg.Version, false,
+			); err != nil {
+				return errors.New("could not force version").SetParent(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status returns every known migration and whether it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]*State, error) {
+	var states []*State
+	err := m.run(ctx, func(conn *sql.Conn, applied map[uint]bool) error {
+		for version := range applied {
+			if m.find(version) == nil {
+				return errors.New("applied migration is unknown").SetArg("version", version)
+			}
+		}
+
+		states = make([]*State, 0, len(m.migrations))
+		for _, mg := range m.migrations {
+			dirty, has := applied[mg.Version]
+			states = append(states, &State{Migration: mg, Applied: has, Dirty: dirty})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (m *Migrator) find(version uint) *Migration {
+	for _, mg := range m.migrations {
+		if mg.Version == version {
+			return mg
+		}
+	}
+
+	return nil
+}
+
+// run calls fn holding the migration lock on a single connection.
+// applied maps each applied version to its dirty flag.
+func (m *Migrator) run(ctx context.Context, fn func(*sql.Conn, map[uint]bool) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return errors.New("could not get connection").SetParent(err)
+	}
+	defer conn.Close()
+
+	if err := m.dialect.lock(ctx, conn); err != nil {
+		return err
+	}
+	defer func() {
+		if er := m.dialect.unlock(ctx, conn, err); er != nil && err == nil {
+			err = errors.New("could not unlock").SetParent(er)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return errors.New("could not create schema_migrations").SetParent(err)
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, dirty FROM schema_migrations")
+	if err != nil {
+		return errors.New("could not fetch schema_migrations").SetParent(err)
+	}
+	defer rows.Close()
+
+	applied := map[uint]bool{}
+	for rows.Next() {
+		var version uint
+		var dirty bool
+		if err := rows.Scan(&version, &dirty); err != nil {
+			return errors.New("could not scan schema_migrations").SetParent(err)
+		}
+		applied[version] = dirty
+	}
+	if err := rows.Err(); err != nil {
+		return errors.New("could not fetch schema_migrations").SetParent(err)
+	}
+	rows.Close()
+
+	return fn(conn, applied)
+}
+
+func checkDirty(applied map[uint]bool) error {
+	for version, dirty := range applied {
+		if dirty {
+			return errors.New("could not migrate").SetArg("version", version).SetParent(ErrDirty)
+		}
+	}
+
+	return nil
+}
+
+func exec(ctx context.Context, conn *sql.Conn, body string) error {
+	for _, statement := range rxStatement.Split(body, -1) {
+		if len(strings.TrimSpace(statement)) == 0 {
+			continue
+		}
+
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
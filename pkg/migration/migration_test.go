@@ -0,0 +1,163 @@
+package migration_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/migration"
+	"github.com/rafaelsq/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+func open(t *testing.T, dsn string) *sql.DB {
+	db, err := sql.Open("sqlite", dsn)
+	require.Nil(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestLoad(t *testing.T) {
+	mysql, err := migration.Load(migration.MySQL)
+	require.Nil(t, err)
+	sqlite, err := migration.Load(migration.SQLite)
+	require.Nil(t, err)
+
+	// both dialects have the same contiguous versions
+	require.Equal(t, len(mysql), len(sqlite))
+	for i := range mysql {
+		assert.Equal(t, uint(i+1), mysql[i].Version)
+		assert.Equal(t, mysql[i].Version, sqlite[i].Version)
+		assert.Equal(t, mysql[i].Name, sqlite[i].Name)
+	}
+
+	_, err = migration.Load("oracle")
+	assert.Equal(t, migration.ErrUnknownDialect, err)
+}
+
+func TestUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := open(t, filepath.Join(t.TempDir(), "boiler.db"))
+
+	m, err := migration.New(db, migration.SQLite)
+	require.Nil(t, err)
+
+	all, err := migration.Load(migration.SQLite)
+	require.Nil(t, err)
+
+	// nothing applied
+	{
+		states, err := m.Status(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, states, len(all))
+		for _, s := range states {
+			assert.False(t, s.Applied)
+		}
+	}
+
+	// up applies all; a second up is a no-op
+	{
+		assert.Nil(t, m.Up(ctx))
+		assert.Nil(t, m.Up(ctx))
+
+		states, err := m.Status(ctx)
+		assert.Nil(t, err)
+		for _, s := range states {
+			assert.True(t, s.Applied)
+			assert.False(t, s.Dirty)
+		}
+
+		_, err = db.Exec("INSERT INTO users (name, created, updated) VALUES ('a', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)")
+		assert.Nil(t, err)
+	}
+
+	// down reverts
+	{
+		assert.Nil(t, m.Down(ctx, len(all)))
+
+		states, err := m.Status(ctx)
+		assert.Nil(t, err)
+		for _, s := range states {
+			assert.False(t, s.Applied)
+		}
+
+		_, err = db.Exec("SELECT 1 FROM users")
+		assert.NotNil(t, err)
+	}
+
+	// down with nothing applied is a no-op
+	assert.Nil(t, m.Down(ctx, 1))
+}
+
+func TestForce(t *testing.T) {
+	ctx := context.Background()
+	db := open(t, filepath.Join(t.TempDir(), "boiler.db"))
+
+	m, err := migration.New(db, migration.SQLite)
+	require.Nil(t, err)
+	require.Nil(t, m.Up(ctx))
+
+	// a dirty schema blocks up and down
+	_, err = db.Exec("UPDATE schema_migrations SET dirty = 1 WHERE version = 1")
+	require.Nil(t, err)
+
+	assert.Equal(t, migration.ErrDirty, errors.Cause(m.Up(ctx)))
+	assert.Equal(t, migration.ErrDirty, errors.Cause(m.Down(ctx, 1)))
+
+	// force cleans it
+	assert.Nil(t, m.Force(ctx, 1))
+
+	states, err := m.Status(ctx)
+	assert.Nil(t, err)
+	assert.True(t, states[0].Applied)
+	assert.False(t, states[0].Dirty)
+
+	// force 0 forgets every migration without touching the schema
+	assert.Nil(t, m.Force(ctx, 0))
+
+	states, err = m.Status(ctx)
+	assert.Nil(t, err)
+	for _, s := range states {
+		assert.False(t, s.Applied)
+	}
+
+	_, err = db.Exec("SELECT 1 FROM users")
+	assert.Nil(t, err)
+
+	// fails if unknown
+	assert.Equal(t, migration.ErrUnknownVersion, m.Force(ctx, 999))
+}
+
+func TestConcurrentUp(t *testing.T) {
+	ctx := context.Background()
+	dsn := filepath.Join(t.TempDir(), "boiler.db") + "?_pragma=busy_timeout(5000)"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		m, err := migration.New(open(t, dsn), migration.SQLite)
+		require.Nil(t, err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, m.Up(ctx))
+		}()
+	}
+	wg.Wait()
+
+	m, err := migration.New(open(t, dsn), migration.SQLite)
+	require.Nil(t, err)
+
+	states, err := m.Status(ctx)
+	assert.Nil(t, err)
+	for _, s := range states {
+		assert.True(t, s.Applied)
+		assert.False(t, s.Dirty)
+	}
+}
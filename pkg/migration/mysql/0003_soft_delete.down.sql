@@ -0,0 +1,12 @@
+-- soft deleted rows would become live again, so they are purged
+DELETE FROM emails WHERE deleted_at IS NOT NULL OR user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL);
+
+ALTER TABLE emails
+  DROP INDEX address,
+  DROP COLUMN active,
+  DROP COLUMN deleted_at,
+  ADD UNIQUE KEY address(address);
+
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN deleted_at DATETIME NULL;
+
+-- active is NULL once an email is deleted, so its address can be reused
+ALTER TABLE emails
+  ADD COLUMN deleted_at DATETIME NULL,
+  ADD COLUMN active TINYINT(1) AS (IF(deleted_at IS NULL, 1, NULL)) VIRTUAL,
+  DROP INDEX address,
+  ADD UNIQUE KEY address(address, active);
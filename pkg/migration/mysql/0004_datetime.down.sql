@@ -0,0 +1,18 @@
+-- back to the global time zone NOW() wrote in; see 0004_datetime.up.sql
+UPDATE users SET
+  created = COALESCE(CONVERT_TZ(created, '+00:00', @@global.time_zone), created),
+  updated = COALESCE(CONVERT_TZ(updated, '+00:00', @@global.time_zone), updated),
+  deleted_at = COALESCE(CONVERT_TZ(deleted_at, '+00:00', @@global.time_zone), deleted_at);
+
+UPDATE emails SET
+  created = COALESCE(CONVERT_TZ(created, '+00:00', @@global.time_zone), created),
+  deleted_at = COALESCE(CONVERT_TZ(deleted_at, '+00:00', @@global.time_zone), deleted_at);
+
+ALTER TABLE users
+  MODIFY created DATE NOT NULL,
+  MODIFY updated DATE NOT NULL,
+  MODIFY deleted_at DATETIME NULL;
+
+ALTER TABLE emails
+  MODIFY created DATE NOT NULL,
+  MODIFY deleted_at DATETIME NULL;
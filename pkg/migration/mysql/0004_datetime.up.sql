@@ -0,0 +1,22 @@
+-- times keep their time of day and fraction of a second, in UTC; those written
+-- so far by NOW() are in the time zone of the server's connections, which set
+-- none of their own, so they are converted from the global one. A named global
+-- zone needs the time zone tables loaded (mysql_tzinfo_to_sql); without them
+-- CONVERT_TZ gives NULL, and the conversion is left undone rather than lost.
+ALTER TABLE users
+  MODIFY created DATETIME(6) NOT NULL,
+  MODIFY updated DATETIME(6) NOT NULL,
+  MODIFY deleted_at DATETIME(6) NULL;
+
+ALTER TABLE emails
+  MODIFY created DATETIME(6) NOT NULL,
+  MODIFY deleted_at DATETIME(6) NULL;
+
+UPDATE users SET
+  created = COALESCE(CONVERT_TZ(created, @@global.time_zone, '+00:00'), created),
+  updated = COALESCE(CONVERT_TZ(updated, @@global.time_zone, '+00:00'), updated),
+  deleted_at = COALESCE(CONVERT_TZ(deleted_at, @@global.time_zone, '+00:00'), deleted_at);
+
+UPDATE emails SET
+  created = COALESCE(CONVERT_TZ(created, @@global.time_zone, '+00:00'), created),
+  deleted_at = COALESCE(CONVERT_TZ(deleted_at, @@global.time_zone, '+00:00'), deleted_at);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS users (
+  id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(255) NOT NULL,
+  created DATETIME NOT NULL,
+  updated DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS emails (
+  id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  address VARCHAR(255) NOT NULL,
+  created DATETIME NOT NULL,
+
+  UNIQUE(address)
+);
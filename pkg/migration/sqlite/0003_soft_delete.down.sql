@@ -0,0 +1,21 @@
+-- soft deleted rows would become live again, so they are purged
+DELETE FROM emails WHERE deleted_at IS NOT NULL OR user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL);
+
+CREATE TABLE emails_old (
+  id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  address VARCHAR(255) NOT NULL,
+  created DATETIME NOT NULL,
+
+  UNIQUE(address)
+);
+
+INSERT INTO emails_old (id, user_id, address, created) SELECT id, user_id, address, created FROM emails;
+
+DROP TABLE emails;
+
+ALTER TABLE emails_old RENAME TO emails;
+
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN deleted_at DATETIME NULL;
+
+-- SQLite cannot drop the UNIQUE(address) constraint, so the table is rebuilt
+-- with a partial index that lets a deleted email's address be reused
+CREATE TABLE emails_new (
+  id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  address VARCHAR(255) NOT NULL,
+  created DATETIME NOT NULL,
+  deleted_at DATETIME NULL
+);
+
+INSERT INTO emails_new (id, user_id, address, created) SELECT id, user_id, address, created FROM emails;
+
+DROP TABLE emails;
+
+ALTER TABLE emails_new RENAME TO emails;
+
+CREATE UNIQUE INDEX emails_address ON emails(address) WHERE deleted_at IS NULL;
@@ -0,0 +1,8 @@
+UPDATE users SET
+  created = strftime('%Y-%m-%d %H:%M:%S', created),
+  updated = strftime('%Y-%m-%d %H:%M:%S', updated),
+  deleted_at = strftime('%Y-%m-%d %H:%M:%S', deleted_at);
+
+UPDATE emails SET
+  created = strftime('%Y-%m-%d %H:%M:%S', created),
+  deleted_at = strftime('%Y-%m-%d %H:%M:%S', deleted_at);
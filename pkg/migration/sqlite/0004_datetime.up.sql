@@ -0,0 +1,10 @@
+-- times are now written with milliseconds; the older ones are rewritten alike
+-- so that the stored text still sorts as the times do
+UPDATE users SET
+  created = strftime('%Y-%m-%d %H:%M:%f', created),
+  updated = strftime('%Y-%m-%d %H:%M:%f', updated),
+  deleted_at = strftime('%Y-%m-%d %H:%M:%f', deleted_at);
+
+UPDATE emails SET
+  created = strftime('%Y-%m-%d %H:%M:%f', created),
+  deleted_at = strftime('%Y-%m-%d %H:%M:%f', deleted_at);
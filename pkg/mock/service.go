@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/rafaelsq/boiler/pkg/entity"
+	event "github.com/rafaelsq/boiler/pkg/event"
 	iface "github.com/rafaelsq/boiler/pkg/iface"
 )
 
@@ -51,27 +52,56 @@ func (mr *MockServiceMockRecorder) AddUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockService)(nil).AddUser), arg0, arg1)
 }
 
+// UpdateUser mocks base method
+func (m *MockService) UpdateUser(arg0 context.Context, arg1, arg2 int64, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser
+func (mr *MockServiceMockRecorder) UpdateUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockService)(nil).UpdateUser), arg0, arg1, arg2, arg3)
+}
+
 // DeleteUser mocks base method
-func (m *MockService) DeleteUser(arg0 context.Context, arg1 int64) error {
+func (m *MockService) DeleteUser(arg0 context.Context, arg1, arg2 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser
-func (mr *MockServiceMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockService)(nil).DeleteUser), arg0, arg1, arg2)
+}
+
+// RestoreUser mocks base method
+func (m *MockService) RestoreUser(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUser indicates an expected call of RestoreUser
+func (mr *MockServiceMockRecorder) RestoreUser(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockService)(nil).DeleteUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockService)(nil).RestoreUser), arg0, arg1, arg2)
 }
 
 // FilterUsers mocks base method
-func (m *MockService) FilterUsers(arg0 context.Context, arg1 iface.FilterUsers) ([]*entity.User, error) {
+func (m *MockService) FilterUsers(arg0 context.Context, arg1 iface.FilterUsers) ([]*entity.User, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FilterUsers", arg0, arg1)
 	ret0, _ := ret[0].([]*entity.User)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // FilterUsers indicates an expected call of FilterUsers
@@ -80,6 +110,21 @@ func (mr *MockServiceMockRecorder) FilterUsers(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterUsers", reflect.TypeOf((*MockService)(nil).FilterUsers), arg0, arg1)
 }
 
+// CountUsers mocks base method
+func (m *MockService) CountUsers(arg0 context.Context, arg1 iface.FilterUsers) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers
+func (mr *MockServiceMockRecorder) CountUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockService)(nil).CountUsers), arg0, arg1)
+}
+
 // GetUserByID mocks base method
 func (m *MockService) GetUserByID(arg0 context.Context, arg1 int64) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +140,26 @@ func (mr *MockServiceMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockService)(nil).GetUserByID), arg0, arg1)
 }
 
+// GetUsersByID mocks base method
+func (m *MockService) GetUsersByID(arg0 context.Context, arg1 ...int64) ([]*entity.User, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUsersByID", varargs...)
+	ret0, _ := ret[0].([]*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByID indicates an expected call of GetUsersByID
+func (mr *MockServiceMockRecorder) GetUsersByID(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByID", reflect.TypeOf((*MockService)(nil).GetUsersByID), varargs...)
+}
+
 // GetUserByEmail mocks base method
 func (m *MockService) GetUserByEmail(arg0 context.Context, arg1 string) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -111,12 +176,13 @@ func (mr *MockServiceMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomoc
 }
 
 // FilterEmails mocks base method
-func (m *MockService) FilterEmails(arg0 context.Context, arg1 iface.FilterEmails) ([]*entity.Email, error) {
+func (m *MockService) FilterEmails(arg0 context.Context, arg1 iface.FilterEmails) ([]*entity.Email, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FilterEmails", arg0, arg1)
 	ret0, _ := ret[0].([]*entity.Email)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // FilterEmails indicates an expected call of FilterEmails
@@ -125,6 +191,36 @@ func (mr *MockServiceMockRecorder) FilterEmails(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterEmails", reflect.TypeOf((*MockService)(nil).FilterEmails), arg0, arg1)
 }
 
+// CountEmails mocks base method
+func (m *MockService) CountEmails(arg0 context.Context, arg1 iface.FilterEmails) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEmails", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEmails indicates an expected call of CountEmails
+func (mr *MockServiceMockRecorder) CountEmails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEmails", reflect.TypeOf((*MockService)(nil).CountEmails), arg0, arg1)
+}
+
+// FilterEmailsByUserIDs mocks base method
+func (m *MockService) FilterEmailsByUserIDs(arg0 context.Context, arg1 iface.FilterEmails, arg2 []int64) (map[int64]*iface.EmailsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterEmailsByUserIDs", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[int64]*iface.EmailsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterEmailsByUserIDs indicates an expected call of FilterEmailsByUserIDs
+func (mr *MockServiceMockRecorder) FilterEmailsByUserIDs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterEmailsByUserIDs", reflect.TypeOf((*MockService)(nil).FilterEmailsByUserIDs), arg0, arg1, arg2)
+}
+
 // AddEmail mocks base method
 func (m *MockService) AddEmail(arg0 context.Context, arg1 int64, arg2 string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -153,3 +249,17 @@ func (mr *MockServiceMockRecorder) DeleteEmail(arg0, arg1 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEmail", reflect.TypeOf((*MockService)(nil).DeleteEmail), arg0, arg1)
 }
+
+// Subscribe mocks base method
+func (m *MockService) Subscribe(arg0 context.Context, arg1 event.Kind) <-chan event.Event {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", arg0, arg1)
+	ret0, _ := ret[0].(<-chan event.Event)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe
+func (mr *MockServiceMockRecorder) Subscribe(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockService)(nil).Subscribe), arg0, arg1)
+}
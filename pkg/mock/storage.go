@@ -6,13 +6,63 @@ package mock
 
 import (
 	context "context"
-	sql "database/sql"
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/rafaelsq/boiler/pkg/entity"
 	iface "github.com/rafaelsq/boiler/pkg/iface"
 	reflect "reflect"
 )
 
+// MockTx is a mock of Tx interface
+type MockTx struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxMockRecorder
+}
+
+// MockTxMockRecorder is the mock recorder for MockTx
+type MockTxMockRecorder struct {
+	mock *MockTx
+}
+
+// NewMockTx creates a new mock instance
+func NewMockTx(ctrl *gomock.Controller) *MockTx {
+	mock := &MockTx{ctrl: ctrl}
+	mock.recorder = &MockTxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTx) EXPECT() *MockTxMockRecorder {
+	return m.recorder
+}
+
+// Commit mocks base method
+func (m *MockTx) Commit() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit
+func (mr *MockTxMockRecorder) Commit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockTx)(nil).Commit))
+}
+
+// Rollback mocks base method
+func (m *MockTx) Rollback() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback
+func (mr *MockTxMockRecorder) Rollback() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockTx)(nil).Rollback))
+}
+
 // MockStorage is a mock of Storage interface
 type MockStorage struct {
 	ctrl     *gomock.Controller
@@ -37,10 +87,10 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 }
 
 // Tx mocks base method
-func (m *MockStorage) Tx() (*sql.Tx, error) {
+func (m *MockStorage) Tx() (iface.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Tx")
-	ret0, _ := ret[0].(*sql.Tx)
+	ret0, _ := ret[0].(iface.Tx)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -52,7 +102,7 @@ func (mr *MockStorageMockRecorder) Tx() *gomock.Call {
 }
 
 // AddUser mocks base method
-func (m *MockStorage) AddUser(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+func (m *MockStorage) AddUser(ctx context.Context, tx iface.Tx, name string) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddUser", ctx, tx, name)
 	ret0, _ := ret[0].(int64)
@@ -66,27 +116,56 @@ func (mr *MockStorageMockRecorder) AddUser(ctx, tx, name interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockStorage)(nil).AddUser), ctx, tx, name)
 }
 
+// UpdateUser mocks base method
+func (m *MockStorage) UpdateUser(ctx context.Context, tx iface.Tx, userID, version int64, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, tx, userID, version, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser
+func (mr *MockStorageMockRecorder) UpdateUser(ctx, tx, userID, version, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStorage)(nil).UpdateUser), ctx, tx, userID, version, name)
+}
+
 // DeleteUser mocks base method
-func (m *MockStorage) DeleteUser(ctx context.Context, tx *sql.Tx, userID int64) error {
+func (m *MockStorage) DeleteUser(ctx context.Context, tx iface.Tx, userID, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", ctx, tx, userID)
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, tx, userID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser
-func (mr *MockStorageMockRecorder) DeleteUser(ctx, tx, userID interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) DeleteUser(ctx, tx, userID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStorage)(nil).DeleteUser), ctx, tx, userID, version)
+}
+
+// RestoreUser mocks base method
+func (m *MockStorage) RestoreUser(ctx context.Context, tx iface.Tx, userID, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, tx, userID, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUser indicates an expected call of RestoreUser
+func (mr *MockStorageMockRecorder) RestoreUser(ctx, tx, userID, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStorage)(nil).DeleteUser), ctx, tx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockStorage)(nil).RestoreUser), ctx, tx, userID, version)
 }
 
 // FilterUsersID mocks base method
-func (m *MockStorage) FilterUsersID(ctx context.Context, filter iface.FilterUsers) ([]int64, error) {
+func (m *MockStorage) FilterUsersID(ctx context.Context, filter iface.FilterUsers) ([]int64, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FilterUsersID", ctx, filter)
 	ret0, _ := ret[0].([]int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // FilterUsersID indicates an expected call of FilterUsersID
@@ -95,10 +174,25 @@ func (mr *MockStorageMockRecorder) FilterUsersID(ctx, filter interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterUsersID", reflect.TypeOf((*MockStorage)(nil).FilterUsersID), ctx, filter)
 }
 
+// CountUsers mocks base method
+func (m *MockStorage) CountUsers(ctx context.Context, filter iface.FilterUsers) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers
+func (mr *MockStorageMockRecorder) CountUsers(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockStorage)(nil).CountUsers), ctx, filter)
+}
+
 // FetchUsers mocks base method
-func (m *MockStorage) FetchUsers(ctx context.Context, ID ...int64) ([]*entity.User, error) {
+func (m *MockStorage) FetchUsers(ctx context.Context, includeDeleted bool, ID ...int64) ([]*entity.User, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx}
+	varargs := []interface{}{ctx, includeDeleted}
 	for _, a := range ID {
 		varargs = append(varargs, a)
 	}
@@ -109,14 +203,14 @@ func (m *MockStorage) FetchUsers(ctx context.Context, ID ...int64) ([]*entity.Us
 }
 
 // FetchUsers indicates an expected call of FetchUsers
-func (mr *MockStorageMockRecorder) FetchUsers(ctx interface{}, ID ...interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) FetchUsers(ctx, includeDeleted interface{}, ID ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx}, ID...)
+	varargs := append([]interface{}{ctx, includeDeleted}, ID...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUsers", reflect.TypeOf((*MockStorage)(nil).FetchUsers), varargs...)
 }
 
 // AddEmail mocks base method
-func (m *MockStorage) AddEmail(ctx context.Context, tx *sql.Tx, userID int64, address string) (int64, error) {
+func (m *MockStorage) AddEmail(ctx context.Context, tx iface.Tx, userID int64, address string) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddEmail", ctx, tx, userID, address)
 	ret0, _ := ret[0].(int64)
@@ -131,7 +225,7 @@ func (mr *MockStorageMockRecorder) AddEmail(ctx, tx, userID, address interface{}
 }
 
 // DeleteEmail mocks base method
-func (m *MockStorage) DeleteEmail(ctx context.Context, tx *sql.Tx, emailID int64) error {
+func (m *MockStorage) DeleteEmail(ctx context.Context, tx iface.Tx, emailID int64) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteEmail", ctx, tx, emailID)
 	ret0, _ := ret[0].(error)
@@ -145,7 +239,7 @@ func (mr *MockStorageMockRecorder) DeleteEmail(ctx, tx, emailID interface{}) *go
 }
 
 // DeleteEmailsByUserID mocks base method
-func (m *MockStorage) DeleteEmailsByUserID(ctx context.Context, tx *sql.Tx, userID int64) error {
+func (m *MockStorage) DeleteEmailsByUserID(ctx context.Context, tx iface.Tx, userID int64) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteEmailsByUserID", ctx, tx, userID)
 	ret0, _ := ret[0].(error)
@@ -159,12 +253,13 @@ func (mr *MockStorageMockRecorder) DeleteEmailsByUserID(ctx, tx, userID interfac
 }
 
 // FilterEmails mocks base method
-func (m *MockStorage) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, error) {
+func (m *MockStorage) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FilterEmails", ctx, filter)
 	ret0, _ := ret[0].([]*entity.Email)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // FilterEmails indicates an expected call of FilterEmails
@@ -172,3 +267,33 @@ func (mr *MockStorageMockRecorder) FilterEmails(ctx, filter interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterEmails", reflect.TypeOf((*MockStorage)(nil).FilterEmails), ctx, filter)
 }
+
+// CountEmails mocks base method
+func (m *MockStorage) CountEmails(ctx context.Context, filter iface.FilterEmails) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEmails", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEmails indicates an expected call of CountEmails
+func (mr *MockStorageMockRecorder) CountEmails(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEmails", reflect.TypeOf((*MockStorage)(nil).CountEmails), ctx, filter)
+}
+
+// FilterEmailsByUserIDs mocks base method
+func (m *MockStorage) FilterEmailsByUserIDs(ctx context.Context, filter iface.FilterEmails, userIDs []int64) (map[int64]*iface.EmailsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterEmailsByUserIDs", ctx, filter, userIDs)
+	ret0, _ := ret[0].(map[int64]*iface.EmailsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterEmailsByUserIDs indicates an expected call of FilterEmailsByUserIDs
+func (mr *MockStorageMockRecorder) FilterEmailsByUserIDs(ctx, filter, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterEmailsByUserIDs", reflect.TypeOf((*MockStorage)(nil).FilterEmailsByUserIDs), ctx, filter, userIDs)
+}
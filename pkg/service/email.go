@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/event"
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/errors"
 )
@@ -27,6 +28,7 @@ func (s *Service) AddEmail(ctx context.Context, userID int64, address string) (i
 		return 0, errors.New("could not add email").SetParent(err)
 	}
 
+	s.bus.Publish(event.Event{Kind: event.EmailAdded, UserID: userID, EmailID: ID})
 	return ID, nil
 }
 
@@ -51,9 +53,18 @@ func (s *Service) DeleteEmail(ctx context.Context, emailID int64) error {
 		return errors.New("could not commit delete email").SetParent(err)
 	}
 
+	s.bus.Publish(event.Event{Kind: event.EmailDeleted, EmailID: emailID})
 	return nil
 }
 
-func (s *Service) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, error) {
+func (s *Service) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, string, error) {
 	return s.storage.FilterEmails(ctx, filter)
 }
+
+func (s *Service) CountEmails(ctx context.Context, filter iface.FilterEmails) (int64, error) {
+	return s.storage.CountEmails(ctx, filter)
+}
+
+func (s *Service) FilterEmailsByUserIDs(ctx context.Context, filter iface.FilterEmails, userIDs []int64) (map[int64]*iface.EmailsPage, error) {
+	return s.storage.FilterEmailsByUserIDs(ctx, filter, userIDs)
+}
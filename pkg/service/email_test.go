@@ -11,6 +11,8 @@ import (
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/mock"
 	"github.com/rafaelsq/boiler/pkg/service"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/rafaelsq/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -276,10 +278,79 @@ func TestFilterEmails(t *testing.T) {
 			ID:      ID,
 			UserID:  userID,
 			Address: address,
-		}}, nil)
+		}}, "", nil)
 
-	es, err := srv.FilterEmails(ctx, filter)
+	es, _, err := srv.FilterEmails(ctx, filter)
 	assert.Nil(t, err)
 	assert.Len(t, es, 1)
 	assert.Equal(t, es[0].ID, ID)
 }
+
+func TestFilterEmailsByUserIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockStorage(ctrl)
+
+	srv := service.New(m)
+
+	filter := iface.FilterEmails{Limit: 2}
+	userIDs := []int64{1, 2}
+	ctx := context.Background()
+	m.
+		EXPECT().
+		FilterEmailsByUserIDs(ctx, filter, userIDs).
+		Return(map[int64]*iface.EmailsPage{
+			1: {Emails: []*entity.Email{{ID: 3, UserID: 1}}},
+			2: {Emails: []*entity.Email{}},
+		}, nil)
+
+	pages, err := srv.FilterEmailsByUserIDs(ctx, filter, userIDs)
+	assert.Nil(t, err)
+	assert.Len(t, pages, 2)
+	assert.Equal(t, int64(3), pages[1].Emails[0].ID)
+}
+
+func TestCountEmails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockStorage(ctrl)
+
+	srv := service.New(m)
+
+	filter := iface.FilterEmails{UserID: 99}
+	ctx := context.Background()
+	m.
+		EXPECT().
+		CountEmails(ctx, filter).
+		Return(int64(2), nil)
+
+	n, err := srv.CountEmails(ctx, filter)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestEmailWithStorage(t *testing.T) {
+	srv := service.New(memory.New())
+	ctx := context.Background()
+
+	emailID, err := srv.AddEmail(ctx, 1, "contact@example.com")
+	assert.Nil(t, err)
+
+	// fails if address already exists; nothing is written
+	{
+		_, err := srv.AddEmail(ctx, 2, "contact@example.com")
+		assert.Equal(t, iface.ErrAlreadyExists, errors.Cause(err))
+
+		es, _, err := srv.FilterEmails(ctx, iface.FilterEmails{UserID: 2})
+		assert.Nil(t, err)
+		assert.Len(t, es, 0)
+	}
+
+	// delete
+	{
+		assert.Nil(t, srv.DeleteEmail(ctx, emailID))
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(srv.DeleteEmail(ctx, emailID)))
+	}
+}
@@ -1,13 +1,22 @@
 package service
 
 import (
+	"context"
+
+	"github.com/rafaelsq/boiler/pkg/event"
 	"github.com/rafaelsq/boiler/pkg/iface"
 )
 
 func New(storage iface.Storage) iface.Service {
-	return &Service{storage}
+	return &Service{storage, event.New()}
 }
 
 type Service struct {
 	storage iface.Storage
+	bus     *event.Bus
+}
+
+// Subscribe returns the changes of kind the service commits until ctx is done.
+func (s *Service) Subscribe(ctx context.Context, kind event.Kind) <-chan event.Event {
+	return s.bus.Subscribe(ctx, kind)
 }
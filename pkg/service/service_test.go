@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/rafaelsq/boiler/pkg/event"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/mock"
+	"github.com/rafaelsq/boiler/pkg/service"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/rafaelsq/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// publishes each committed change
+	{
+		srv := service.New(memory.New())
+
+		usersAdded := srv.Subscribe(ctx, event.UserAdded)
+		usersDeleted := srv.Subscribe(ctx, event.UserDeleted)
+		emailsAdded := srv.Subscribe(ctx, event.EmailAdded)
+		emailsDeleted := srv.Subscribe(ctx, event.EmailDeleted)
+
+		userID, err := srv.AddUser(ctx, "John Doe")
+		assert.Nil(t, err)
+		assert.Equal(t, event.Event{Kind: event.UserAdded, UserID: userID}, <-usersAdded)
+
+		emailID, err := srv.AddEmail(ctx, userID, "john@example.com")
+		assert.Nil(t, err)
+		assert.Equal(t, event.Event{Kind: event.EmailAdded, UserID: userID, EmailID: emailID}, <-emailsAdded)
+
+		assert.Nil(t, srv.DeleteEmail(ctx, emailID))
+		assert.Equal(t, event.Event{Kind: event.EmailDeleted, EmailID: emailID}, <-emailsDeleted)
+
+		assert.Nil(t, srv.DeleteUser(ctx, userID, 1))
+		assert.Equal(t, event.Event{Kind: event.UserDeleted, UserID: userID}, <-usersDeleted)
+
+		// deleting it again fails and publishes nothing
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(srv.DeleteUser(ctx, userID, 2)))
+		assert.Len(t, usersDeleted, 0)
+	}
+
+	// does not publish if commit fails
+	{
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := mock.NewMockStorage(ctrl)
+		srv := service.New(m)
+		usersAdded := srv.Subscribe(ctx, event.UserAdded)
+
+		db, mdb, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mdb.ExpectBegin()
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+
+		m.EXPECT().Tx().Return(tx, nil)
+		m.EXPECT().AddUser(ctx, tx, "John Doe").Return(int64(1), nil)
+		mdb.ExpectCommit().WillReturnError(fmt.Errorf("opz"))
+
+		_, err = srv.AddUser(ctx, "John Doe")
+		assert.NotNil(t, err)
+		assert.Len(t, usersAdded, 0)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/event"
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/errors"
 )
@@ -29,17 +30,43 @@ func (s *Service) AddUser(ctx context.Context, name string) (int64, error) {
 		return 0, errors.New("could not add user").SetParent(err)
 	}
 
+	s.bus.Publish(event.Event{Kind: event.UserAdded, UserID: ID})
 	return ID, nil
 }
 
-func (s *Service) DeleteUser(ctx context.Context, userID int64) error {
+func (s *Service) UpdateUser(ctx context.Context, userID, version int64, name string) error {
+	tx, err := s.storage.Tx()
+	if err != nil {
+		return errors.New("could not begin update user transaction").SetParent(err)
+	}
+
+	err = s.storage.UpdateUser(ctx, tx, userID, version, name)
+	if err != nil {
+		if er := tx.Rollback(); er != nil {
+			return errors.New("could not rollback update user").SetParent(
+				errors.New(er.Error()).SetParent(err),
+			)
+		}
+
+		return errors.New("could not update user").SetParent(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.New("could not commit update user").SetParent(err)
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteUser(ctx context.Context, userID, version int64) error {
 	tx, err := s.storage.Tx()
 	if err != nil {
 		return errors.New("could not begin delete user transaction").SetParent(err)
 	}
 
-	err = s.storage.DeleteUser(ctx, tx, userID)
-	if err != nil && err != iface.ErrNotFound {
+	// the user's emails stay, hidden with it, so RestoreUser brings them back
+	err = s.storage.DeleteUser(ctx, tx, userID, version)
+	if err != nil {
 		if er := tx.Rollback(); er != nil {
 			return errors.New("could not rollback delete user").SetParent(
 				errors.New(er.Error()).SetParent(err),
@@ -49,35 +76,58 @@ func (s *Service) DeleteUser(ctx context.Context, userID int64) error {
 		return errors.New("could not delete user").SetParent(err)
 	}
 
-	err = s.storage.DeleteEmailsByUserID(ctx, tx, userID)
-	if err != nil && err != iface.ErrNotFound {
+	if err := tx.Commit(); err != nil {
+		return errors.New("could not commit delete user").SetParent(err)
+	}
+
+	s.bus.Publish(event.Event{Kind: event.UserDeleted, UserID: userID})
+	return nil
+}
+
+func (s *Service) RestoreUser(ctx context.Context, userID, version int64) error {
+	tx, err := s.storage.Tx()
+	if err != nil {
+		return errors.New("could not begin restore user transaction").SetParent(err)
+	}
+
+	err = s.storage.RestoreUser(ctx, tx, userID, version)
+	if err != nil {
 		if er := tx.Rollback(); er != nil {
-			return errors.New("could not rollback delete emails by user ID").SetParent(
+			return errors.New("could not rollback restore user").SetParent(
 				errors.New(er.Error()).SetParent(err),
 			)
 		}
 
-		return errors.New("could not delete user emails").SetParent(err)
+		return errors.New("could not restore user").SetParent(err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return errors.New("could not commit delete user").SetParent(err)
+		return errors.New("could not commit restore user").SetParent(err)
 	}
 
 	return nil
 }
 
-func (s *Service) FilterUsers(ctx context.Context, filter iface.FilterUsers) ([]*entity.User, error) {
-	IDs, err := s.storage.FilterUsersID(ctx, filter)
+func (s *Service) FilterUsers(ctx context.Context, filter iface.FilterUsers) ([]*entity.User, string, error) {
+	IDs, next, err := s.storage.FilterUsersID(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	users, err := s.storage.FetchUsers(ctx, filter.IncludeDeleted, IDs...)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return s.storage.FetchUsers(ctx, IDs...)
+	return users, next, nil
+}
+
+func (s *Service) CountUsers(ctx context.Context, filter iface.FilterUsers) (int64, error) {
+	return s.storage.CountUsers(ctx, filter)
 }
 
 func (s *Service) GetUserByID(ctx context.Context, userID int64) (*entity.User, error) {
-	us, err := s.storage.FetchUsers(ctx, userID)
+	us, err := s.storage.FetchUsers(ctx, false, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -87,8 +137,13 @@ func (s *Service) GetUserByID(ctx context.Context, userID int64) (*entity.User,
 	return us[0], nil
 }
 
+// GetUsersByID returns the users found, in the order of IDs.
+func (s *Service) GetUsersByID(ctx context.Context, IDs ...int64) ([]*entity.User, error) {
+	return s.storage.FetchUsers(ctx, false, IDs...)
+}
+
 func (s *Service) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
-	IDs, err := s.storage.FilterUsersID(ctx, iface.FilterUsers{Email: email})
+	IDs, _, err := s.storage.FilterUsersID(ctx, iface.FilterUsers{Email: email})
 	if err != nil {
 		return nil, err
 	}
@@ -11,6 +11,8 @@ import (
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/mock"
 	"github.com/rafaelsq/boiler/pkg/service"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/rafaelsq/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -139,7 +141,7 @@ func TestAddUser(t *testing.T) {
 	}
 }
 
-func TestDeleteUser(t *testing.T) {
+func TestUpdateUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -148,6 +150,8 @@ func TestDeleteUser(t *testing.T) {
 	srv := service.New(m)
 
 	var userID int64 = 99
+	var version int64 = 2
+	name := "Jane"
 
 	ctx := context.Background()
 
@@ -165,15 +169,130 @@ func TestDeleteUser(t *testing.T) {
 		m.EXPECT().Tx().Return(tx, nil)
 		m.
 			EXPECT().
-			DeleteUser(ctx, tx, userID).
+			UpdateUser(ctx, tx, userID, version, name).
+			Return(nil)
+		mdb.ExpectCommit()
+
+		err = srv.UpdateUser(ctx, userID, version, name)
+		assert.Nil(t, err)
+		assert.Nil(t, mdb.ExpectationsWereMet())
+	}
+
+	// fails if Tx fails
+	{
+		m.EXPECT().Tx().Return(nil, fmt.Errorf("tx fails"))
+
+		err := srv.UpdateUser(ctx, userID, version, name)
+		assert.NotNil(t, err)
+		assert.Equal(t, err.Error(), "could not begin update user transaction; tx fails")
+	}
+
+	// UpdateUser fail
+	{
+		db, mdb, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mdb.ExpectBegin()
+
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+
+		m.EXPECT().Tx().Return(tx, nil)
+		m.
+			EXPECT().
+			UpdateUser(ctx, tx, userID, version, name).
+			Return(iface.ErrNotFound)
+		mdb.ExpectRollback()
+
+		err = srv.UpdateUser(ctx, userID, version, name)
+		assert.NotNil(t, err)
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(err))
+		assert.Nil(t, mdb.ExpectationsWereMet())
+	}
+
+	// UpdateUser rollback fail
+	{
+		db, mdb, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mdb.ExpectBegin()
+
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+
+		m.EXPECT().Tx().Return(tx, nil)
+		m.
+			EXPECT().
+			UpdateUser(ctx, tx, userID, version, name).
+			Return(fmt.Errorf("updatefail"))
+
+		mdb.ExpectRollback().WillReturnError(fmt.Errorf("rollbackfail"))
+
+		err = srv.UpdateUser(ctx, userID, version, name)
+		assert.NotNil(t, err)
+		assert.Equal(t, "could not rollback update user; rollbackfail; updatefail", err.Error())
+		assert.Nil(t, mdb.ExpectationsWereMet())
+	}
+
+	// commit fail
+	{
+		db, mdb, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mdb.ExpectBegin()
+
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+
+		m.EXPECT().Tx().Return(tx, nil)
+		m.
+			EXPECT().
+			UpdateUser(ctx, tx, userID, version, name).
 			Return(nil)
+		mdb.ExpectCommit().WillReturnError(fmt.Errorf("commitfail"))
+
+		err = srv.UpdateUser(ctx, userID, version, name)
+		assert.NotNil(t, err)
+		assert.Equal(t, "could not commit update user; commitfail", err.Error())
+		assert.Nil(t, mdb.ExpectationsWereMet())
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockStorage(ctrl)
+
+	srv := service.New(m)
+
+	var userID int64 = 99
+	var version int64 = 2
+
+	ctx := context.Background()
+
+	// succeed
+	{
+		db, mdb, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mdb.ExpectBegin()
+
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+
+		m.EXPECT().Tx().Return(tx, nil)
 		m.
 			EXPECT().
-			DeleteEmailsByUserID(ctx, tx, userID).
+			DeleteUser(ctx, tx, userID, version).
 			Return(nil)
 		mdb.ExpectCommit()
 
-		err = srv.DeleteUser(ctx, userID)
+		err = srv.DeleteUser(ctx, userID, version)
 		assert.Nil(t, err)
 		assert.Nil(t, mdb.ExpectationsWereMet())
 	}
@@ -182,7 +301,7 @@ func TestDeleteUser(t *testing.T) {
 	{
 		m.EXPECT().Tx().Return(nil, fmt.Errorf("tx fails"))
 
-		err := srv.DeleteUser(ctx, userID)
+		err := srv.DeleteUser(ctx, userID, version)
 		assert.NotNil(t, err)
 		assert.Equal(t, err.Error(), "could not begin delete user transaction; tx fails")
 	}
@@ -201,16 +320,39 @@ func TestDeleteUser(t *testing.T) {
 		m.EXPECT().Tx().Return(tx, nil)
 		m.
 			EXPECT().
-			DeleteUser(ctx, tx, userID).
+			DeleteUser(ctx, tx, userID, version).
 			Return(fmt.Errorf("deletefail"))
 		mdb.ExpectRollback()
 
-		err = srv.DeleteUser(ctx, userID)
+		err = srv.DeleteUser(ctx, userID, version)
 		assert.NotNil(t, err)
 		assert.Equal(t, "could not delete user; deletefail", err.Error())
 		assert.Nil(t, mdb.ExpectationsWereMet())
 	}
 
+	// fails if the user is missing or deleted
+	{
+		db, mdb, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mdb.ExpectBegin()
+
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+
+		m.EXPECT().Tx().Return(tx, nil)
+		m.
+			EXPECT().
+			DeleteUser(ctx, tx, userID, version).
+			Return(iface.ErrNotFound)
+		mdb.ExpectRollback()
+
+		err = srv.DeleteUser(ctx, userID, version)
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(err))
+		assert.Nil(t, mdb.ExpectationsWereMet())
+	}
+
 	// DeleteUser rollback fail
 	{
 		db, mdb, err := sqlmock.New()
@@ -225,18 +367,18 @@ func TestDeleteUser(t *testing.T) {
 		m.EXPECT().Tx().Return(tx, nil)
 		m.
 			EXPECT().
-			DeleteUser(ctx, tx, userID).
+			DeleteUser(ctx, tx, userID, version).
 			Return(fmt.Errorf("deletefail"))
 
 		mdb.ExpectRollback().WillReturnError(fmt.Errorf("rollbackfail"))
 
-		err = srv.DeleteUser(ctx, userID)
+		err = srv.DeleteUser(ctx, userID, version)
 		assert.NotNil(t, err)
 		assert.Equal(t, "could not rollback delete user; rollbackfail; deletefail", err.Error())
 		assert.Nil(t, mdb.ExpectationsWereMet())
 	}
 
-	// DeleteEmail fail
+	// commit fail
 	{
 		db, mdb, err := sqlmock.New()
 		assert.Nil(t, err)
@@ -250,21 +392,31 @@ func TestDeleteUser(t *testing.T) {
 		m.EXPECT().Tx().Return(tx, nil)
 		m.
 			EXPECT().
-			DeleteUser(ctx, tx, userID).
+			DeleteUser(ctx, tx, userID, version).
 			Return(nil)
-		m.
-			EXPECT().
-			DeleteEmailsByUserID(ctx, tx, userID).
-			Return(fmt.Errorf("deletefail"))
-		mdb.ExpectRollback()
+		mdb.ExpectCommit().WillReturnError(fmt.Errorf("commitfail"))
 
-		err = srv.DeleteUser(ctx, userID)
+		err = srv.DeleteUser(ctx, userID, version)
 		assert.NotNil(t, err)
-		assert.Equal(t, "could not delete user emails; deletefail", err.Error())
+		assert.Equal(t, "could not commit delete user; commitfail", err.Error())
 		assert.Nil(t, mdb.ExpectationsWereMet())
 	}
+}
+
+func TestRestoreUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockStorage(ctrl)
+
+	srv := service.New(m)
+
+	var userID int64 = 99
+	var version int64 = 2
 
-	// DeleteEmail rollback fail
+	ctx := context.Background()
+
+	// succeed
 	{
 		db, mdb, err := sqlmock.New()
 		assert.Nil(t, err)
@@ -278,18 +430,70 @@ func TestDeleteUser(t *testing.T) {
 		m.EXPECT().Tx().Return(tx, nil)
 		m.
 			EXPECT().
-			DeleteUser(ctx, tx, userID).
+			RestoreUser(ctx, tx, userID, version).
 			Return(nil)
+		mdb.ExpectCommit()
+
+		err = srv.RestoreUser(ctx, userID, version)
+		assert.Nil(t, err)
+		assert.Nil(t, mdb.ExpectationsWereMet())
+	}
+
+	// fails if Tx fails
+	{
+		m.EXPECT().Tx().Return(nil, fmt.Errorf("tx fails"))
+
+		err := srv.RestoreUser(ctx, userID, version)
+		assert.NotNil(t, err)
+		assert.Equal(t, err.Error(), "could not begin restore user transaction; tx fails")
+	}
+
+	// RestoreUser fail
+	{
+		db, mdb, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mdb.ExpectBegin()
+
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+
+		m.EXPECT().Tx().Return(tx, nil)
 		m.
 			EXPECT().
-			DeleteEmailsByUserID(ctx, tx, userID).
-			Return(fmt.Errorf("deletefail"))
+			RestoreUser(ctx, tx, userID, version).
+			Return(iface.ErrNotFound)
+		mdb.ExpectRollback()
+
+		err = srv.RestoreUser(ctx, userID, version)
+		assert.NotNil(t, err)
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(err))
+		assert.Nil(t, mdb.ExpectationsWereMet())
+	}
+
+	// RestoreUser rollback fail
+	{
+		db, mdb, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mdb.ExpectBegin()
+
+		tx, err := db.Begin()
+		assert.Nil(t, err)
+
+		m.EXPECT().Tx().Return(tx, nil)
+		m.
+			EXPECT().
+			RestoreUser(ctx, tx, userID, version).
+			Return(fmt.Errorf("restorefail"))
 
 		mdb.ExpectRollback().WillReturnError(fmt.Errorf("rollbackfail"))
 
-		err = srv.DeleteUser(ctx, userID)
+		err = srv.RestoreUser(ctx, userID, version)
 		assert.NotNil(t, err)
-		assert.Equal(t, "could not rollback delete emails by user ID; rollbackfail; deletefail", err.Error())
+		assert.Equal(t, "could not rollback restore user; rollbackfail; restorefail", err.Error())
 		assert.Nil(t, mdb.ExpectationsWereMet())
 	}
 
@@ -307,17 +511,13 @@ func TestDeleteUser(t *testing.T) {
 		m.EXPECT().Tx().Return(tx, nil)
 		m.
 			EXPECT().
-			DeleteUser(ctx, tx, userID).
-			Return(nil)
-		m.
-			EXPECT().
-			DeleteEmailsByUserID(ctx, tx, userID).
+			RestoreUser(ctx, tx, userID, version).
 			Return(nil)
 		mdb.ExpectCommit().WillReturnError(fmt.Errorf("commitfail"))
 
-		err = srv.DeleteUser(ctx, userID)
+		err = srv.RestoreUser(ctx, userID, version)
 		assert.NotNil(t, err)
-		assert.Equal(t, "could not commit delete user; commitfail", err.Error())
+		assert.Equal(t, "could not commit restore user; commitfail", err.Error())
 		assert.Nil(t, mdb.ExpectationsWereMet())
 	}
 }
@@ -340,35 +540,72 @@ func TestFilterUsersID(t *testing.T) {
 		m.
 			EXPECT().
 			FilterUsersID(ctx, filter).
-			Return([]int64{userID}, nil)
+			Return([]int64{userID}, iface.EncodeCursor(userID), nil)
 		m.
 			EXPECT().
-			FetchUsers(ctx, userID).
+			FetchUsers(ctx, false, userID).
 			Return([]*entity.User{{
 				ID:   userID,
 				Name: name,
 			}}, nil)
 
-		vs, err := srv.FilterUsers(ctx, filter)
+		vs, next, err := srv.FilterUsers(ctx, filter)
 		assert.Nil(t, err)
 		assert.Len(t, vs, 1)
 		assert.Equal(t, vs[0].ID, userID)
 		assert.Equal(t, vs[0].Name, name)
+		assert.Equal(t, iface.EncodeCursor(userID), next)
 	}
 
-	// fail
+	// fails if fetch fails
 	{
 		m.
 			EXPECT().
 			FilterUsersID(ctx, filter).
+			Return([]int64{userID}, "", nil)
+		m.
+			EXPECT().
+			FetchUsers(ctx, false, userID).
 			Return(nil, fmt.Errorf("opz"))
 
-		IDs, err := srv.FilterUsers(ctx, filter)
+		vs, _, err := srv.FilterUsers(ctx, filter)
+		assert.Nil(t, vs)
+		assert.Equal(t, err.Error(), "opz")
+	}
+
+	// fail
+	{
+		m.
+			EXPECT().
+			FilterUsersID(ctx, filter).
+			Return(nil, "", fmt.Errorf("opz"))
+
+		IDs, _, err := srv.FilterUsers(ctx, filter)
 		assert.Nil(t, IDs)
 		assert.Equal(t, err.Error(), "opz")
 	}
 }
 
+func TestCountUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockStorage(ctrl)
+
+	srv := service.New(m)
+
+	filter := iface.FilterUsers{IncludeDeleted: true}
+	ctx := context.Background()
+	m.
+		EXPECT().
+		CountUsers(ctx, filter).
+		Return(int64(3), nil)
+
+	n, err := srv.CountUsers(ctx, filter)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
 func TestGetUserByID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -386,7 +623,7 @@ func TestGetUserByID(t *testing.T) {
 	{
 		m.
 			EXPECT().
-			FetchUsers(ctx, userID).
+			FetchUsers(ctx, false, userID).
 			Return([]*entity.User{
 				{
 					ID:   userID,
@@ -404,7 +641,7 @@ func TestGetUserByID(t *testing.T) {
 	{
 		m.
 			EXPECT().
-			FetchUsers(ctx, userID).
+			FetchUsers(ctx, false, userID).
 			Return(nil, fmt.Errorf("opz"))
 
 		v, err := srv.GetUserByID(ctx, userID)
@@ -417,7 +654,7 @@ func TestGetUserByID(t *testing.T) {
 	{
 		m.
 			EXPECT().
-			FetchUsers(ctx, userID).
+			FetchUsers(ctx, false, userID).
 			Return([]*entity.User{}, nil)
 
 		v, err := srv.GetUserByID(ctx, userID)
@@ -426,6 +663,41 @@ func TestGetUserByID(t *testing.T) {
 	}
 }
 
+func TestGetUsersByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockStorage(ctrl)
+
+	srv := service.New(m)
+
+	ctx := context.Background()
+
+	// succeed
+	{
+		m.
+			EXPECT().
+			FetchUsers(ctx, false, int64(1), int64(2)).
+			Return([]*entity.User{{ID: 1}, {ID: 2}}, nil)
+
+		users, err := srv.GetUsersByID(ctx, 1, 2)
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+	}
+
+	// fails if storage fails
+	{
+		m.
+			EXPECT().
+			FetchUsers(ctx, false, int64(1)).
+			Return(nil, fmt.Errorf("opz"))
+
+		users, err := srv.GetUsersByID(ctx, 1)
+		assert.Nil(t, users)
+		assert.Equal(t, err.Error(), "opz")
+	}
+}
+
 func TestGetUserByEmail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -445,10 +717,10 @@ func TestGetUserByEmail(t *testing.T) {
 		m.
 			EXPECT().
 			FilterUsersID(ctx, iface.FilterUsers{Email: email}).
-			Return([]int64{userID}, nil)
+			Return([]int64{userID}, "", nil)
 		m.
 			EXPECT().
-			FetchUsers(ctx, userID).
+			FetchUsers(ctx, false, userID).
 			Return([]*entity.User{
 				{
 					ID:   userID,
@@ -467,7 +739,7 @@ func TestGetUserByEmail(t *testing.T) {
 		m.
 			EXPECT().
 			FilterUsersID(ctx, iface.FilterUsers{Email: email}).
-			Return(nil, fmt.Errorf("opz"))
+			Return(nil, "", fmt.Errorf("opz"))
 
 		v, err := srv.GetUserByEmail(ctx, email)
 		assert.Nil(t, v)
@@ -480,10 +752,79 @@ func TestGetUserByEmail(t *testing.T) {
 		m.
 			EXPECT().
 			FilterUsersID(ctx, iface.FilterUsers{Email: email}).
-			Return([]int64{}, nil)
+			Return([]int64{}, "", nil)
 
 		v, err := srv.GetUserByEmail(ctx, email)
 		assert.Nil(t, v)
 		assert.Equal(t, iface.ErrNotFound, err)
 	}
 }
+
+func TestUserWithStorage(t *testing.T) {
+	srv := service.New(memory.New())
+	ctx := context.Background()
+
+	userID, err := srv.AddUser(ctx, "John Doe")
+	assert.Nil(t, err)
+
+	_, err = srv.AddEmail(ctx, userID, "john@example.com")
+	assert.Nil(t, err)
+
+	// get by email
+	{
+		u, err := srv.GetUserByEmail(ctx, "john@example.com")
+		assert.Nil(t, err)
+		assert.Equal(t, userID, u.ID)
+		assert.Equal(t, "John Doe", u.Name)
+	}
+
+	// update bumps the version; a stale one conflicts
+	{
+		assert.Nil(t, srv.UpdateUser(ctx, userID, 1, "Jane Doe"))
+
+		u, err := srv.GetUserByID(ctx, userID)
+		assert.Nil(t, err)
+		assert.Equal(t, "Jane Doe", u.Name)
+		assert.Equal(t, int64(2), u.Version)
+
+		err = srv.UpdateUser(ctx, userID, 1, "John Doe")
+		assert.Equal(t, iface.ErrConflict, errors.Cause(err))
+		err = srv.DeleteUser(ctx, userID, 1)
+		assert.Equal(t, iface.ErrConflict, errors.Cause(err))
+	}
+
+	// delete hides the user and its emails
+	{
+		assert.Nil(t, srv.DeleteUser(ctx, userID, 2))
+
+		err := srv.DeleteUser(ctx, userID, 3)
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(err))
+
+		u, err := srv.GetUserByID(ctx, userID)
+		assert.Nil(t, u)
+		assert.Equal(t, iface.ErrNotFound, err)
+
+		es, _, err := srv.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, es, 0)
+
+		us, _, err := srv.FilterUsers(ctx, iface.FilterUsers{IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Len(t, us, 1)
+	}
+
+	// restore brings both back; a stale version conflicts
+	{
+		err := srv.RestoreUser(ctx, userID, 2)
+		assert.Equal(t, iface.ErrConflict, errors.Cause(err))
+
+		assert.Nil(t, srv.RestoreUser(ctx, userID, 3))
+
+		u, err := srv.GetUserByEmail(ctx, "john@example.com")
+		assert.Nil(t, err)
+		assert.Equal(t, userID, u.ID)
+
+		err = srv.RestoreUser(ctx, userID, 4)
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(err))
+	}
+}
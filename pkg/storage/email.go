@@ -3,6 +3,8 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rafaelsq/boiler/pkg/entity"
@@ -10,44 +12,175 @@ import (
 	"github.com/rafaelsq/errors"
 )
 
-func (s *Storage) AddEmail(ctx context.Context, tx *sql.Tx, userID int64, address string) (int64, error) {
+func (s *Storage) AddEmail(ctx context.Context, tx iface.Tx, userID int64, address string) (int64, error) {
 	return Insert(ctx, tx,
-		"INSERT INTO emails (user_id, address, created) VALUES (?, ?, NOW())",
+		"INSERT INTO emails (user_id, address, created) VALUES (?, ?, "+now+")",
 		userID, address,
 	)
 }
 
-func (s *Storage) DeleteEmail(ctx context.Context, tx *sql.Tx, emailID int64) error {
-	return Delete(ctx, tx, "DELETE FROM emails WHERE id = ?", emailID)
+func (s *Storage) DeleteEmail(ctx context.Context, tx iface.Tx, emailID int64) error {
+	return DeleteEmail(ctx, tx, now, emailID)
 }
 
-func (s *Storage) DeleteEmailsByUserID(ctx context.Context, tx *sql.Tx, userID int64) error {
-	return Delete(ctx, tx, "DELETE FROM emails WHERE user_id = ?", userID)
+func (s *Storage) DeleteEmailsByUserID(ctx context.Context, tx iface.Tx, userID int64) error {
+	return DeleteEmailsByUserID(ctx, tx, now, userID)
 }
 
-func (s *Storage) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, error) {
+func (s *Storage) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, string, error) {
+	return FilterEmails(ctx, s.sql, filter)
+}
+
+func (s *Storage) CountEmails(ctx context.Context, filter iface.FilterEmails) (int64, error) {
+	return CountEmails(ctx, s.sql, filter)
+}
+
+func (s *Storage) FilterEmailsByUserIDs(ctx context.Context, filter iface.FilterEmails, userIDs []int64) (map[int64]*iface.EmailsPage, error) {
+	return FilterEmailsByUserIDs(ctx, s.sql, filter, userIDs)
+}
+
+// DeleteEmail soft deletes the email within tx; now is the storage's expression of
+// the current time in UTC.
+func DeleteEmail(ctx context.Context, tx iface.Tx, now string, emailID int64) error {
+	return Delete(ctx, tx, "UPDATE emails SET deleted_at = "+now+" WHERE id = ? AND deleted_at IS NULL", emailID)
+}
+
+// DeleteEmailsByUserID soft deletes the emails of the user within tx; now is the
+// storage's expression of the current time in UTC.
+func DeleteEmailsByUserID(ctx context.Context, tx iface.Tx, now string, userID int64) error {
+	return Delete(ctx, tx,
+		"UPDATE emails SET deleted_at = "+now+" WHERE user_id = ? AND deleted_at IS NULL",
+		userID,
+	)
+}
+
+// emailsFilter returns the conditions and their args of an emails filter, without its page.
+func emailsFilter(filter iface.FilterEmails) ([]string, []interface{}) {
+	conds := []string{"e.user_id = ?"}
 	args := []interface{}{filter.UserID}
-	where := "user_id = ?"
 	if filter.EmailID > 0 {
-		where = "id = ?"
+		conds = []string{"e.id = ?"}
 		args = []interface{}{filter.EmailID}
 	}
 
-	rows, err := Select(ctx, s.sql, scanEmail,
-		"SELECT id, user_id, address, created FROM emails WHERE "+where,
+	if !filter.IncludeDeleted {
+		conds = append(conds, "e.deleted_at IS NULL", "u.deleted_at IS NULL")
+	}
+
+	return conds, args
+}
+
+// FilterEmails returns a page of the emails matching filter and the cursor of the next one.
+func FilterEmails(ctx context.Context, db *sql.DB, filter iface.FilterEmails) ([]*entity.Email, string, error) {
+	limit := iface.FilterEmailsDefaultLimit
+	if filter.Limit != 0 {
+		limit = filter.Limit
+	}
+
+	cmp, order, bound, err := Keyset(filter.Cursor, filter.Backward)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// one row over the limit tells whether there is a following page
+	conds, args := emailsFilter(filter)
+	conds = append(conds, "e.id "+cmp+" ?")
+	args = append(args, bound, limit+1)
+
+	rows, err := Select(ctx, db, scanEmail,
+		"SELECT e.id, e.user_id, e.address, e.created, e.deleted_at "+
+			"FROM emails e LEFT JOIN users u ON(u.id = e.user_id) "+
+			"WHERE "+strings.Join(conds, " AND ")+" ORDER BY e.id"+order+" LIMIT ?",
 		args...,
 	)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
+	rows, next := Page(rows, limit, filter.Backward, emailID)
+
 	emails := make([]*entity.Email, 0, len(rows))
 	for _, row := range rows {
 		emails = append(emails, row.(*entity.Email))
+	}
+
+	return emails, next, nil
+}
+
+// CountEmails returns how many emails match filter, whatever its page.
+func CountEmails(ctx context.Context, db *sql.DB, filter iface.FilterEmails) (int64, error) {
+	conds, args := emailsFilter(filter)
+
+	return Count(ctx, db,
+		"SELECT COUNT(*) FROM emails e LEFT JOIN users u ON(u.id = e.user_id) WHERE "+strings.Join(conds, " AND "),
+		args...,
+	)
+}
+
+// FilterEmailsByUserIDs pages the emails of each user as FilterEmails would, in one query.
+func FilterEmailsByUserIDs(ctx context.Context, db *sql.DB, filter iface.FilterEmails, userIDs []int64) (map[int64]*iface.EmailsPage, error) {
+	pages := make(map[int64]*iface.EmailsPage, len(userIDs))
+	if len(userIDs) == 0 {
+		return pages, nil
+	}
+
+	limit := iface.FilterEmailsDefaultLimit
+	if filter.Limit != 0 {
+		limit = filter.Limit
+	}
+
+	cmp, order, bound, err := Keyset(filter.Cursor, filter.Backward)
+	if err != nil {
+		return nil, err
+	}
+
+	conds := []string{fmt.Sprintf("e.user_id IN (%s)", strings.Repeat("?,", len(userIDs))[0:len(userIDs)*2-1])}
+	args := make([]interface{}, 0, len(userIDs)+2)
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+	if !filter.IncludeDeleted {
+		conds = append(conds, "e.deleted_at IS NULL", "u.deleted_at IS NULL")
+	}
+	conds = append(conds, "e.id "+cmp+" ?")
+	args = append(args, bound, limit+1)
 
+	// numbering each user's rows keeps one over the limit per user
+	rows, err := Select(ctx, db, scanEmail,
+		"SELECT id, user_id, address, created, deleted_at FROM ("+
+			"SELECT e.id, e.user_id, e.address, e.created, e.deleted_at, "+
+			"ROW_NUMBER() OVER(PARTITION BY e.user_id ORDER BY e.id"+order+") AS n "+
+			"FROM emails e LEFT JOIN users u ON(u.id = e.user_id) "+
+			"WHERE "+strings.Join(conds, " AND ")+
+			") p WHERE n <= ? ORDER BY user_id, id"+order,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[int64][]interface{}, len(userIDs))
+	for _, row := range rows {
+		userID := row.(*entity.Email).UserID
+		byUser[userID] = append(byUser[userID], row)
 	}
 
-	return emails, nil
+	for _, userID := range userIDs {
+		rows, next := Page(byUser[userID], limit, filter.Backward, emailID)
+
+		emails := make([]*entity.Email, 0, len(rows))
+		for _, row := range rows {
+			emails = append(emails, row.(*entity.Email))
+		}
+
+		pages[userID] = &iface.EmailsPage{Emails: emails, Next: next}
+	}
+
+	return pages, nil
+}
+
+func emailID(row interface{}) int64 {
+	return row.(*entity.Email).ID
 }
 
 func scanEmail(sc func(dest ...interface{}) error) (interface{}, error) {
@@ -55,8 +188,9 @@ func scanEmail(sc func(dest ...interface{}) error) (interface{}, error) {
 	var userID int64
 	var address string
 	var created time.Time
+	var deletedAt *time.Time
 
-	err := sc(&id, &userID, &address, &created)
+	err := sc(&id, &userID, &address, &created, &deletedAt)
 	if err != nil {
 		return nil, errors.New("could not scan email").SetParent(err)
 	}
@@ -65,6 +199,8 @@ func scanEmail(sc func(dest ...interface{}) error) (interface{}, error) {
 		ID:      id,
 		UserID:  userID,
 		Address: address,
-		Created: created,
+		Created: created.UTC(),
+
+		DeletedAt: UTC(deletedAt),
 	}, nil
 }
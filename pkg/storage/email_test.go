@@ -12,6 +12,7 @@ import (
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/storage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAddEmail(t *testing.T) {
@@ -30,7 +31,7 @@ func TestAddEmail(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("INSERT INTO emails (user_id, address, created) VALUES (?, ?, NOW())"),
+			regexp.QuoteMeta("INSERT INTO emails (user_id, address, created) VALUES (?, ?, UTC_TIMESTAMP(6))"),
 		).WithArgs(userID, address).WillReturnResult(sqlmock.NewResult(3, 1))
 		mock.ExpectCommit()
 
@@ -53,7 +54,7 @@ func TestAddEmail(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("INSERT INTO emails (user_id, address, created) VALUES (?, ?, NOW())"),
+			regexp.QuoteMeta("INSERT INTO emails (user_id, address, created) VALUES (?, ?, UTC_TIMESTAMP(6))"),
 		).WithArgs(userID, address).WillReturnError(myErr)
 		mock.ExpectCommit()
 
@@ -79,7 +80,7 @@ func TestAddEmail(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("INSERT INTO emails (user_id, address, created) VALUES (?, ?, NOW())"),
+			regexp.QuoteMeta("INSERT INTO emails (user_id, address, created) VALUES (?, ?, UTC_TIMESTAMP(6))"),
 		).WithArgs(userID, address).WillReturnError(&myErr)
 		mock.ExpectCommit()
 
@@ -102,7 +103,7 @@ func TestAddEmail(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("INSERT INTO emails (user_id, address, created) VALUES (?, ?, NOW())"),
+			regexp.QuoteMeta("INSERT INTO emails (user_id, address, created) VALUES (?, ?, UTC_TIMESTAMP(6))"),
 		).WithArgs(userID, address).WillReturnResult(sqlmock.NewResult(3, 1)).WillReturnResult(sqlmock.NewErrorResult(myErr))
 		mock.ExpectCommit()
 
@@ -132,7 +133,7 @@ func TestDeleteEmail(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM emails WHERE id = ?"),
+			regexp.QuoteMeta("UPDATE emails SET deleted_at = UTC_TIMESTAMP(6) WHERE id = ? AND deleted_at IS NULL"),
 		).WithArgs(emailID).WillReturnResult(sqlmock.NewResult(3, 1))
 		mock.ExpectCommit()
 
@@ -153,7 +154,7 @@ func TestDeleteEmail(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM emails WHERE id = ?"),
+			regexp.QuoteMeta("UPDATE emails SET deleted_at = UTC_TIMESTAMP(6) WHERE id = ? AND deleted_at IS NULL"),
 		).WithArgs(emailID).WillReturnError(fmt.Errorf("opz"))
 
 		r := storage.New(mdb)
@@ -172,7 +173,7 @@ func TestDeleteEmail(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM emails WHERE id = ?"),
+			regexp.QuoteMeta("UPDATE emails SET deleted_at = UTC_TIMESTAMP(6) WHERE id = ? AND deleted_at IS NULL"),
 		).WithArgs(emailID).
 			WillReturnResult(sqlmock.NewResult(1, 1)).
 			WillReturnResult(sqlmock.NewErrorResult(fmt.Errorf("opz")))
@@ -197,7 +198,7 @@ func TestDeleteEmail(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM emails WHERE id = ?"),
+			regexp.QuoteMeta("UPDATE emails SET deleted_at = UTC_TIMESTAMP(6) WHERE id = ? AND deleted_at IS NULL"),
 		).WithArgs(emailID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
@@ -230,7 +231,7 @@ func TestDeleteEmailsByUserID(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM emails WHERE user_id = ?"),
+			regexp.QuoteMeta("UPDATE emails SET deleted_at = UTC_TIMESTAMP(6) WHERE user_id = ? AND deleted_at IS NULL"),
 		).WithArgs(userID).WillReturnResult(sqlmock.NewResult(3, 1))
 		mock.ExpectCommit()
 
@@ -251,7 +252,7 @@ func TestDeleteEmailsByUserID(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM emails WHERE user_id = ?"),
+			regexp.QuoteMeta("UPDATE emails SET deleted_at = UTC_TIMESTAMP(6) WHERE user_id = ? AND deleted_at IS NULL"),
 		).WithArgs(userID).WillReturnError(fmt.Errorf("opz"))
 		mock.ExpectCommit()
 
@@ -281,31 +282,74 @@ func TestFilterEmails(t *testing.T) {
 		userID := int64(3)
 
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT id, user_id, address, created FROM emails WHERE user_id = ?"),
-		).WithArgs(userID).WillReturnRows(
-			sqlmock.NewRows([]string{"id", "user_id", "address", "created"}).
-				AddRow(3, userID, "user@example.com", time.Time{}),
+			regexp.QuoteMeta(
+				"SELECT e.id, e.user_id, e.address, e.created, e.deleted_at "+
+					"FROM emails e LEFT JOIN users u ON(u.id = e.user_id) "+
+					"WHERE e.user_id = ? AND e.deleted_at IS NULL AND u.deleted_at IS NULL AND e.id > ? ORDER BY e.id LIMIT ?",
+			),
+		).WithArgs(userID, 0, iface.FilterEmailsDefaultLimit+1).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "address", "created", "deleted_at"}).
+				AddRow(3, userID, "user@example.com", time.Time{}, nil),
 		)
 
 		r := storage.New(mdb)
-		emails, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
 		assert.Nil(t, err)
 		assert.Len(t, emails, 1)
 	}
 
+	// succeed with next page
+	{
+		userID := int64(3)
+
+		mock.ExpectQuery(
+			regexp.QuoteMeta(
+				"SELECT e.id, e.user_id, e.address, e.created, e.deleted_at "+
+					"FROM emails e LEFT JOIN users u ON(u.id = e.user_id) "+
+					"WHERE e.user_id = ? AND e.deleted_at IS NULL AND u.deleted_at IS NULL AND e.id > ? ORDER BY e.id LIMIT ?",
+			),
+		).WithArgs(userID, 2, 2).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "address", "created", "deleted_at"}).
+				AddRow(3, userID, "a@example.com", time.Time{}, nil).
+				AddRow(4, userID, "b@example.com", time.Time{}, nil),
+		)
+
+		r := storage.New(mdb)
+		emails, next, err := r.FilterEmails(ctx, iface.FilterEmails{
+			UserID: userID,
+			Limit:  1,
+			Cursor: iface.EncodeCursor(2),
+		})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 1)
+		assert.Equal(t, iface.EncodeCursor(3), next)
+	}
+
+	// fails if cursor is invalid
+	{
+		r := storage.New(mdb)
+		emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: 3, Cursor: "opz"})
+		assert.Equal(t, iface.ErrInvalidCursor, err)
+		assert.Nil(t, emails)
+	}
+
 	// filter by emailID
 	{
 		emailID := int64(3)
 
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT id, user_id, address, created FROM emails WHERE id = ?"),
-		).WithArgs(emailID).WillReturnRows(
-			sqlmock.NewRows([]string{"id", "user_id", "address", "created"}).
-				AddRow(3, emailID, "user@example.com", time.Time{}),
+			regexp.QuoteMeta(
+				"SELECT e.id, e.user_id, e.address, e.created, e.deleted_at "+
+					"FROM emails e LEFT JOIN users u ON(u.id = e.user_id) "+
+					"WHERE e.id = ? AND e.deleted_at IS NULL AND u.deleted_at IS NULL AND e.id > ? ORDER BY e.id LIMIT ?",
+			),
+		).WithArgs(emailID, 0, iface.FilterEmailsDefaultLimit+1).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "address", "created", "deleted_at"}).
+				AddRow(3, emailID, "user@example.com", time.Time{}, nil),
 		)
 
 		r := storage.New(mdb)
-		emails, err := r.FilterEmails(ctx, iface.FilterEmails{EmailID: emailID})
+		emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{EmailID: emailID})
 		assert.Nil(t, err)
 		assert.Len(t, emails, 1)
 	}
@@ -315,14 +359,18 @@ func TestFilterEmails(t *testing.T) {
 		userID := int64(3)
 
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT id, user_id, address, created FROM emails WHERE user_id = ?"),
-		).WithArgs(userID).WillReturnRows(
-			sqlmock.NewRows([]string{"id", "user_id", "address", "created"}).
-				AddRow("opz", userID, "user@example.com", 0),
+			regexp.QuoteMeta(
+				"SELECT e.id, e.user_id, e.address, e.created, e.deleted_at "+
+					"FROM emails e LEFT JOIN users u ON(u.id = e.user_id) "+
+					"WHERE e.user_id = ? AND e.deleted_at IS NULL AND u.deleted_at IS NULL AND e.id > ? ORDER BY e.id LIMIT ?",
+			),
+		).WithArgs(userID, 0, iface.FilterEmailsDefaultLimit+1).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "address", "created", "deleted_at"}).
+				AddRow("opz", userID, "user@example.com", 0, nil),
 		)
 
 		r := storage.New(mdb)
-		emails, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "invalid syntax")
 		assert.Len(t, emails, 0)
@@ -334,12 +382,126 @@ func TestFilterEmails(t *testing.T) {
 		myErr := fmt.Errorf("opz")
 
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT id, user_id, address, created FROM emails WHERE user_id = ?"),
-		).WithArgs(userID).WillReturnError(myErr)
+			regexp.QuoteMeta(
+				"SELECT e.id, e.user_id, e.address, e.created, e.deleted_at "+
+					"FROM emails e LEFT JOIN users u ON(u.id = e.user_id) "+
+					"WHERE e.user_id = ? AND e.deleted_at IS NULL AND u.deleted_at IS NULL AND e.id > ? ORDER BY e.id LIMIT ?",
+			),
+		).WithArgs(userID, 0, iface.FilterEmailsDefaultLimit+1).WillReturnError(myErr)
 
 		r := storage.New(mdb)
-		emails, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
 		assert.Equal(t, err.Error(), "could not fetch rows; opz")
 		assert.Len(t, emails, 0)
 	}
 }
+
+func TestCountEmails(t *testing.T) {
+	ctx := context.Background()
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mdb.Close()
+
+	// succeed
+	{
+		userID := int64(3)
+
+		mock.ExpectQuery(
+			regexp.QuoteMeta(
+				"SELECT COUNT(*) FROM emails e LEFT JOIN users u ON(u.id = e.user_id) " +
+					"WHERE e.user_id = ? AND e.deleted_at IS NULL AND u.deleted_at IS NULL",
+			),
+		).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(2))
+
+		r := storage.New(mdb)
+		n, err := r.CountEmails(ctx, iface.FilterEmails{UserID: userID, Cursor: iface.EncodeCursor(1)})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), n)
+	}
+
+	// fail
+	{
+		userID := int64(3)
+
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT COUNT(*) FROM emails e LEFT JOIN users u ON(u.id = e.user_id) WHERE e.user_id = ?"),
+		).WithArgs(userID).WillReturnError(fmt.Errorf("opz"))
+
+		r := storage.New(mdb)
+		n, err := r.CountEmails(ctx, iface.FilterEmails{UserID: userID, IncludeDeleted: true})
+		assert.Equal(t, "could not count rows; opz", err.Error())
+		assert.Equal(t, int64(0), n)
+	}
+}
+
+func TestFilterEmailsByUserIDs(t *testing.T) {
+	ctx := context.Background()
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mdb.Close()
+
+	query := regexp.QuoteMeta(
+		"SELECT id, user_id, address, created, deleted_at FROM (" +
+			"SELECT e.id, e.user_id, e.address, e.created, e.deleted_at, " +
+			"ROW_NUMBER() OVER(PARTITION BY e.user_id ORDER BY e.id) AS n " +
+			"FROM emails e LEFT JOIN users u ON(u.id = e.user_id) " +
+			"WHERE e.user_id IN (?,?,?) AND e.deleted_at IS NULL AND u.deleted_at IS NULL AND e.id > ?" +
+			") p WHERE n <= ? ORDER BY user_id, id",
+	)
+
+	// succeed
+	{
+		mock.ExpectQuery(query).WithArgs(1, 2, 3, 0, 2).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "address", "created", "deleted_at"}).
+				AddRow(4, 1, "a@example.com", time.Time{}, nil).
+				AddRow(5, 1, "b@example.com", time.Time{}, nil).
+				AddRow(6, 2, "c@example.com", time.Time{}, nil),
+		)
+
+		r := storage.New(mdb)
+		pages, err := r.FilterEmailsByUserIDs(ctx, iface.FilterEmails{Limit: 1}, []int64{1, 2, 3})
+		assert.Nil(t, err)
+		require.Len(t, pages, 3)
+
+		require.Len(t, pages[1].Emails, 1)
+		assert.Equal(t, int64(4), pages[1].Emails[0].ID)
+		assert.Equal(t, iface.EncodeCursor(4), pages[1].Next)
+
+		require.Len(t, pages[2].Emails, 1)
+		assert.Equal(t, int64(6), pages[2].Emails[0].ID)
+		assert.Empty(t, pages[2].Next)
+
+		assert.Len(t, pages[3].Emails, 0)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+
+	// succeed without users
+	{
+		r := storage.New(mdb)
+		pages, err := r.FilterEmailsByUserIDs(ctx, iface.FilterEmails{}, nil)
+		assert.Nil(t, err)
+		assert.Len(t, pages, 0)
+	}
+
+	// fails if cursor is invalid
+	{
+		r := storage.New(mdb)
+		pages, err := r.FilterEmailsByUserIDs(ctx, iface.FilterEmails{Cursor: "opz"}, []int64{1})
+		assert.Equal(t, iface.ErrInvalidCursor, err)
+		assert.Nil(t, pages)
+	}
+
+	// fail
+	{
+		mock.ExpectQuery(query).WithArgs(1, 2, 3, 0, iface.FilterEmailsDefaultLimit+1).WillReturnError(fmt.Errorf("opz"))
+
+		r := storage.New(mdb)
+		pages, err := r.FilterEmailsByUserIDs(ctx, iface.FilterEmails{}, []int64{1, 2, 3})
+		assert.Equal(t, "could not fetch rows; opz", err.Error())
+		assert.Nil(t, pages)
+	}
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+)
+
+func (s *Storage) AddEmail(ctx context.Context, itx iface.Tx, userID int64, address string) (int64, error) {
+	tx, unlock, err := s.begin(itx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if len(tx.emailsBy(func(e *entity.Email) bool { return e.Address == address && e.DeletedAt == nil })) != 0 {
+		return 0, iface.ErrAlreadyExists
+	}
+
+	s.mu.Lock()
+	s.lastEmailID++
+	ID := s.lastEmailID
+	s.mu.Unlock()
+
+	tx.emails[ID] = &entity.Email{
+		ID:      ID,
+		UserID:  userID,
+		Address: address,
+		Created: time.Now().UTC(),
+	}
+
+	return ID, nil
+}
+
+func (s *Storage) DeleteEmail(ctx context.Context, itx iface.Tx, emailID int64) error {
+	tx, unlock, err := s.begin(itx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	emails := tx.emailsBy(func(e *entity.Email) bool { return e.ID == emailID && e.DeletedAt == nil })
+	if len(emails) == 0 {
+		return iface.ErrNotFound
+	}
+
+	tx.deleteEmails(emails)
+
+	return nil
+}
+
+func (s *Storage) DeleteEmailsByUserID(ctx context.Context, itx iface.Tx, userID int64) error {
+	tx, unlock, err := s.begin(itx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	emails := tx.emailsBy(func(e *entity.Email) bool { return e.UserID == userID && e.DeletedAt == nil })
+	if len(emails) == 0 {
+		return iface.ErrNotFound
+	}
+
+	tx.deleteEmails(emails)
+
+	return nil
+}
+
+// deleteEmails stages soft deleted copies of emails.
+func (tx *Tx) deleteEmails(emails []*entity.Email) {
+	now := time.Now().UTC()
+	for _, email := range emails {
+		e := *email
+		e.DeletedAt = &now
+		tx.emails[e.ID] = &e
+	}
+}
+
+// filterEmails returns the emails matching filter sorted by ID, without its page; s.mu must be held.
+func (s *Storage) filterEmails(filter iface.FilterEmails) []*entity.Email {
+	emails := []*entity.Email{}
+	for _, email := range s.emails {
+		if filter.EmailID > 0 && email.ID != filter.EmailID {
+			continue
+		}
+
+		if filter.EmailID <= 0 && email.UserID != filter.UserID {
+			continue
+		}
+
+		if user, has := s.users[email.UserID]; !filter.IncludeDeleted &&
+			(email.DeletedAt != nil || (has && user.DeletedAt != nil)) {
+			continue
+		}
+
+		emails = append(emails, email)
+	}
+	sort.Slice(emails, func(i, j int) bool { return emails[i].ID < emails[j].ID })
+
+	return emails
+}
+
+func (s *Storage) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, string, error) {
+	limit := iface.FilterEmailsDefaultLimit
+	if filter.Limit != 0 {
+		limit = filter.Limit
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := s.filterEmails(filter)
+	IDs := make([]int64, 0, len(matches))
+	for _, email := range matches {
+		IDs = append(IDs, email.ID)
+	}
+
+	from, to, next, err := window(IDs, limit, filter.Cursor, filter.Backward, byID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	emails := make([]*entity.Email, 0, to-from)
+	for _, email := range matches[from:to] {
+		e := *email
+		emails = append(emails, &e)
+	}
+
+	return emails, next, nil
+}
+
+func (s *Storage) CountEmails(ctx context.Context, filter iface.FilterEmails) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.filterEmails(filter))), nil
+}
+
+func (s *Storage) FilterEmailsByUserIDs(ctx context.Context, filter iface.FilterEmails, userIDs []int64) (map[int64]*iface.EmailsPage, error) {
+	pages := make(map[int64]*iface.EmailsPage, len(userIDs))
+	for _, userID := range userIDs {
+		filter.UserID = userID
+		emails, next, err := s.FilterEmails(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		pages[userID] = &iface.EmailsPage{Emails: emails, Next: next}
+	}
+
+	return pages, nil
+}
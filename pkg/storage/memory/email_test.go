@@ -0,0 +1,73 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddEmail(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	// succeed
+	emailID, err := r.AddEmail(ctx, tx, 3, "user@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, int(emailID))
+
+	// fails if duplicate in the same tx
+	emailID, err = r.AddEmail(ctx, tx, 4, "user@example.com")
+	assert.Equal(t, iface.ErrAlreadyExists, err)
+	assert.Equal(t, 0, int(emailID))
+	assert.Nil(t, tx.Commit())
+
+	// address is free again once deleted
+	tx, err = r.Tx()
+	assert.Nil(t, err)
+	assert.Nil(t, r.DeleteEmail(ctx, tx, 1))
+
+	emailID, err = r.AddEmail(ctx, tx, 4, "user@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, int(emailID))
+	assert.Nil(t, tx.Commit())
+}
+
+func TestDeleteEmailsByUserID(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	_, err = r.AddEmail(ctx, tx, 3, "a@example.com")
+	assert.Nil(t, err)
+	_, err = r.AddEmail(ctx, tx, 3, "b@example.com")
+	assert.Nil(t, err)
+	_, err = r.AddEmail(ctx, tx, 4, "c@example.com")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	tx, err = r.Tx()
+	assert.Nil(t, err)
+
+	// succeed
+	assert.Nil(t, r.DeleteEmailsByUserID(ctx, tx, 3))
+
+	// fails if not found
+	assert.Equal(t, iface.ErrNotFound, r.DeleteEmailsByUserID(ctx, tx, 3))
+	assert.Nil(t, tx.Commit())
+
+	emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: 3})
+	assert.Nil(t, err)
+	assert.Len(t, emails, 0)
+
+	emails, _, err = r.FilterEmails(ctx, iface.FilterEmails{UserID: 4})
+	assert.Nil(t, err)
+	assert.Len(t, emails, 1)
+}
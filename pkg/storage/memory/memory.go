@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"sync"
+
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/errors"
+)
+
+// ErrInvalidTx is returned when a transaction was not started by the same Storage.
+var ErrInvalidTx = errors.New("invalid transaction")
+
+// Storage keeps users and emails in memory, with times in UTC as the SQL storages; it is safe for concurrent use.
+type Storage struct {
+	mu sync.RWMutex
+
+	users  map[int64]*entity.User
+	emails map[int64]*entity.Email
+
+	lastUserID  int64
+	lastEmailID int64
+}
+
+func New() iface.Storage {
+	return &Storage{
+		users:  map[int64]*entity.User{},
+		emails: map[int64]*entity.Email{},
+	}
+}
+
+// Tx stages writes until Commit; Rollback discards them.
+type Tx struct {
+	mu sync.Mutex
+	s  *Storage
+
+	users  map[int64]*entity.User
+	emails map[int64]*entity.Email
+
+	done bool
+}
+
+func (s *Storage) Tx() (iface.Tx, error) {
+	return &Tx{
+		s:      s,
+		users:  map[int64]*entity.User{},
+		emails: map[int64]*entity.Email{},
+	}, nil
+}
+
+// Commit applies the staged writes atomically.
+// It fails with iface.ErrAlreadyExists if another transaction committed the same address first.
+func (tx *Tx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	tx.s.mu.Lock()
+	defer tx.s.mu.Unlock()
+
+	for ID, email := range tx.emails {
+		if email.DeletedAt != nil {
+			continue
+		}
+
+		for oID, o := range tx.s.emails {
+			if staged, has := tx.emails[oID]; has {
+				o = staged
+			}
+
+			if oID == ID || o.DeletedAt != nil || o.Address != email.Address {
+				continue
+			}
+
+			return iface.ErrAlreadyExists
+		}
+	}
+
+	for ID, user := range tx.users {
+		tx.s.users[ID] = user
+	}
+
+	for ID, email := range tx.emails {
+		tx.s.emails[ID] = email
+	}
+
+	return nil
+}
+
+func (tx *Tx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	return nil
+}
+
+// begin locks tx for a write; the returned func unlocks it.
+func (s *Storage) begin(itx iface.Tx) (*Tx, func(), error) {
+	tx, ok := itx.(*Tx)
+	if !ok || tx.s != s {
+		return nil, nil, ErrInvalidTx
+	}
+
+	tx.mu.Lock()
+	if tx.done {
+		tx.mu.Unlock()
+		return nil, nil, sql.ErrTxDone
+	}
+
+	return tx, tx.mu.Unlock, nil
+}
+
+// user returns the user as seen by tx.
+func (tx *Tx) user(ID int64) *entity.User {
+	if user, has := tx.users[ID]; has {
+		return user
+	}
+
+	tx.s.mu.RLock()
+	defer tx.s.mu.RUnlock()
+
+	return tx.s.users[ID]
+}
+
+// emailsBy returns the emails matched by fn as seen by tx.
+func (tx *Tx) emailsBy(fn func(*entity.Email) bool) []*entity.Email {
+	var emails []*entity.Email
+	for _, email := range tx.emails {
+		if fn(email) {
+			emails = append(emails, email)
+		}
+	}
+
+	tx.s.mu.RLock()
+	defer tx.s.mu.RUnlock()
+
+	for ID, email := range tx.s.emails {
+		if _, has := tx.emails[ID]; !has && fn(email) {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails
+}
+
+// byID lists in ID order.
+func byID(a, b int64) bool {
+	return a < b
+}
+
+// window returns the bounds, within IDs sorted by less, of the page of size limit
+// resuming at cursor, and the cursor of the following page.
+func window(IDs []int64, limit uint, cursor string, backward bool, less func(a, b int64) bool) (int, int, string, error) {
+	bound, err := iface.DecodeCursor(cursor)
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	if !backward {
+		from := 0
+		if bound != 0 {
+			from = sort.Search(len(IDs), func(i int) bool { return less(bound, IDs[i]) })
+		}
+		if uint(len(IDs)-from) > limit {
+			to := from + int(limit)
+			return from, to, iface.EncodeCursor(IDs[to-1]), nil
+		}
+
+		return from, len(IDs), "", nil
+	}
+
+	to := len(IDs)
+	if bound != 0 {
+		to = sort.Search(len(IDs), func(i int) bool { return !less(IDs[i], bound) })
+	}
+	if uint(to) > limit {
+		from := to - int(limit)
+		return from, to, iface.EncodeCursor(IDs[from]), nil
+	}
+
+	return 0, to, "", nil
+}
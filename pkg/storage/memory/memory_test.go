@@ -0,0 +1,127 @@
+package memory_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx(t *testing.T) {
+	ctx := context.Background()
+
+	// commit makes writes visible
+	{
+		r := memory.New()
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		userID, err := r.AddUser(ctx, tx, "user")
+		assert.Nil(t, err)
+
+		users, err := r.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		assert.Nil(t, tx.Commit())
+
+		users, err = r.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+	}
+
+	// rollback discards writes
+	{
+		r := memory.New()
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		userID, err := r.AddUser(ctx, tx, "user")
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Rollback())
+
+		users, err := r.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+	}
+
+	// fails if done
+	{
+		r := memory.New()
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Commit())
+
+		assert.Equal(t, sql.ErrTxDone, tx.Commit())
+		assert.Equal(t, sql.ErrTxDone, tx.Rollback())
+
+		_, err = r.AddUser(ctx, tx, "user")
+		assert.Equal(t, sql.ErrTxDone, err)
+	}
+
+	// fails if tx is from another storage
+	{
+		tx, err := memory.New().Tx()
+		assert.Nil(t, err)
+
+		_, err = memory.New().AddUser(ctx, tx, "user")
+		assert.Equal(t, memory.ErrInvalidTx, err)
+	}
+
+	// fails on commit if another tx took the address
+	{
+		r := memory.New()
+
+		tx1, err := r.Tx()
+		assert.Nil(t, err)
+		tx2, err := r.Tx()
+		assert.Nil(t, err)
+
+		_, err = r.AddEmail(ctx, tx1, 1, "a@example.com")
+		assert.Nil(t, err)
+		_, err = r.AddEmail(ctx, tx2, 2, "a@example.com")
+		assert.Nil(t, err)
+
+		assert.Nil(t, tx1.Commit())
+		assert.Equal(t, iface.ErrAlreadyExists, tx2.Commit())
+
+		emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: 2})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 0)
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			tx, err := r.Tx()
+			assert.Nil(t, err)
+
+			_, err = r.AddUser(ctx, tx, "user")
+			assert.Nil(t, err)
+			assert.Nil(t, tx.Commit())
+
+			_, _, err = r.FilterUsersID(ctx, iface.FilterUsers{})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{})
+	assert.Nil(t, err)
+	assert.Len(t, IDs, 20)
+}
@@ -0,0 +1,247 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+)
+
+func (s *Storage) AddUser(ctx context.Context, itx iface.Tx, name string) (int64, error) {
+	tx, unlock, err := s.begin(itx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	s.mu.Lock()
+	s.lastUserID++
+	ID := s.lastUserID
+	s.mu.Unlock()
+
+	now := time.Now().UTC()
+	tx.users[ID] = &entity.User{
+		ID:      ID,
+		Name:    name,
+		Created: now,
+		Updated: now,
+		Version: 1,
+	}
+
+	return ID, nil
+}
+
+func (s *Storage) UpdateUser(ctx context.Context, itx iface.Tx, userID, version int64, name string) error {
+	tx, unlock, err := s.begin(itx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	user := tx.user(userID)
+	if user == nil || user.DeletedAt != nil {
+		return iface.ErrNotFound
+	}
+
+	if user.Version != version {
+		return iface.ErrConflict
+	}
+
+	u := *user
+	u.Name = name
+	u.Updated = time.Now().UTC()
+	u.Version++
+	tx.users[userID] = &u
+
+	return nil
+}
+
+func (s *Storage) DeleteUser(ctx context.Context, itx iface.Tx, userID, version int64) error {
+	tx, unlock, err := s.begin(itx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	user := tx.user(userID)
+	if user == nil || user.DeletedAt != nil {
+		return iface.ErrNotFound
+	}
+
+	if user.Version != version {
+		return iface.ErrConflict
+	}
+
+	now := time.Now().UTC()
+	u := *user
+	u.DeletedAt = &now
+	u.Updated = now
+	u.Version++
+	tx.users[userID] = &u
+
+	return nil
+}
+
+func (s *Storage) RestoreUser(ctx context.Context, itx iface.Tx, userID, version int64) error {
+	tx, unlock, err := s.begin(itx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	user := tx.user(userID)
+	if user == nil || user.DeletedAt == nil {
+		return iface.ErrNotFound
+	}
+
+	if user.Version != version {
+		return iface.ErrConflict
+	}
+
+	u := *user
+	u.DeletedAt = nil
+	u.Updated = time.Now().UTC()
+	u.Version++
+	tx.users[userID] = &u
+
+	return nil
+}
+
+// filterUsers returns the IDs of the users matching filter, without its page, in its order; s.mu must be held.
+func (s *Storage) filterUsers(filter iface.FilterUsers) []int64 {
+	IDs := []int64{}
+	for ID, user := range s.users {
+		if s.matchUser(user, filter) {
+			IDs = append(IDs, ID)
+		}
+	}
+
+	less := s.userLess(filter)
+	sort.Slice(IDs, func(i, j int) bool { return less(IDs[i], IDs[j]) })
+
+	return IDs
+}
+
+// matchUser reports whether user matches filter, ignoring its page; s.mu must be held.
+func (s *Storage) matchUser(user *entity.User, filter iface.FilterUsers) bool {
+	if !filter.IncludeDeleted && user.DeletedAt != nil {
+		return false
+	}
+
+	// LIKE ignores the case of ASCII letters in both MySQL and SQLite
+	if len(filter.NamePrefix) != 0 && !strings.HasPrefix(strings.ToLower(user.Name), strings.ToLower(filter.NamePrefix)) {
+		return false
+	}
+
+	if !filter.CreatedFrom.IsZero() && user.Created.Before(filter.CreatedFrom) {
+		return false
+	}
+
+	if !filter.CreatedBefore.IsZero() && !user.Created.Before(filter.CreatedBefore) {
+		return false
+	}
+
+	if len(filter.Email) == 0 && len(filter.EmailDomain) == 0 {
+		return true
+	}
+
+	domain := "@" + strings.ToLower(filter.EmailDomain)
+	for _, email := range s.emails {
+		if email.UserID != user.ID || (!filter.IncludeDeleted && email.DeletedAt != nil) {
+			continue
+		}
+
+		if len(filter.Email) != 0 && email.Address != filter.Email {
+			continue
+		}
+
+		if len(filter.EmailDomain) != 0 && !strings.HasSuffix(strings.ToLower(email.Address), domain) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// userLess returns whether user a is listed before user b by filter, both in s.users; s.mu must be held.
+func (s *Storage) userLess(filter iface.FilterUsers) func(a, b int64) bool {
+	return func(a, b int64) bool {
+		ua, ub := s.users[a], s.users[b]
+
+		var c int
+		switch filter.OrderBy {
+		case iface.UserOrderName:
+			c = strings.Compare(ua.Name, ub.Name)
+		case iface.UserOrderCreated:
+			if ua.Created.Before(ub.Created) {
+				c = -1
+			} else if ub.Created.Before(ua.Created) {
+				c = 1
+			}
+		}
+
+		if c == 0 && a != b {
+			c = 1
+			if a < b {
+				c = -1
+			}
+		}
+
+		if filter.Desc {
+			return c > 0
+		}
+		return c < 0
+	}
+}
+
+func (s *Storage) FilterUsersID(ctx context.Context, filter iface.FilterUsers) ([]int64, string, error) {
+	limit := iface.FilterUsersDefaultLimit
+	if filter.Limit != 0 {
+		limit = filter.Limit
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// as in SQL, a page cannot resume after a user missing from the order
+	if bound, err := iface.DecodeCursor(filter.Cursor); err == nil && bound != 0 && filter.OrderBy != iface.UserOrderID {
+		if _, has := s.users[bound]; !has {
+			return []int64{}, "", nil
+		}
+	}
+
+	IDs := s.filterUsers(filter)
+	from, to, next, err := window(IDs, limit, filter.Cursor, filter.Backward, s.userLess(filter))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return IDs[from:to], next, nil
+}
+
+func (s *Storage) CountUsers(ctx context.Context, filter iface.FilterUsers) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.filterUsers(filter))), nil
+}
+
+func (s *Storage) FetchUsers(ctx context.Context, includeDeleted bool, IDs ...int64) ([]*entity.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]*entity.User, 0, len(IDs))
+	for _, ID := range IDs {
+		if user, has := s.users[ID]; has && (includeDeleted || user.DeletedAt == nil) {
+			u := *user
+			users = append(users, &u)
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,96 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	userID, err := r.AddUser(ctx, tx, "user")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	tx, err = r.Tx()
+	assert.Nil(t, err)
+
+	// fails if version is stale
+	assert.Equal(t, iface.ErrConflict, r.DeleteUser(ctx, tx, userID, 2))
+
+	// succeed
+	assert.Nil(t, r.DeleteUser(ctx, tx, userID, 1))
+
+	// fails if deleted in the same tx
+	assert.Equal(t, iface.ErrNotFound, r.DeleteUser(ctx, tx, userID, 1))
+	assert.Nil(t, tx.Commit())
+
+	users, err := r.FetchUsers(ctx, false, userID)
+	assert.Nil(t, err)
+	assert.Len(t, users, 0)
+}
+
+func TestFilterUsersID(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := r.AddUser(ctx, tx, name)
+		assert.Nil(t, err)
+	}
+
+	_, err = r.AddEmail(ctx, tx, 2, "b@example.com")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	// limit
+	{
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{1, 2}, IDs)
+	}
+
+	// by email
+	{
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: "b@example.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{2}, IDs)
+	}
+}
+
+func TestFetchUsers(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := r.AddUser(ctx, tx, name)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tx.Commit())
+
+	users, err := r.FetchUsers(ctx, false, 3, 9, 1)
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "c", users[0].Name)
+	assert.Equal(t, "a", users[1].Name)
+
+	// returns copies
+	users[0].Name = "changed"
+	users, err = r.FetchUsers(ctx, false, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "c", users[0].Name)
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage"
+)
+
+func (s *Storage) AddEmail(ctx context.Context, tx iface.Tx, userID int64, address string) (int64, error) {
+	return Insert(ctx, tx,
+		"INSERT INTO emails (user_id, address, created) VALUES (?, ?, "+now+")",
+		userID, address,
+	)
+}
+
+func (s *Storage) DeleteEmail(ctx context.Context, tx iface.Tx, emailID int64) error {
+	return storage.DeleteEmail(ctx, tx, now, emailID)
+}
+
+func (s *Storage) DeleteEmailsByUserID(ctx context.Context, tx iface.Tx, userID int64) error {
+	return storage.DeleteEmailsByUserID(ctx, tx, now, userID)
+}
+
+func (s *Storage) FilterEmails(ctx context.Context, filter iface.FilterEmails) ([]*entity.Email, string, error) {
+	return storage.FilterEmails(ctx, s.sql, filter)
+}
+
+func (s *Storage) CountEmails(ctx context.Context, filter iface.FilterEmails) (int64, error) {
+	return storage.CountEmails(ctx, s.sql, filter)
+}
+
+func (s *Storage) FilterEmailsByUserIDs(ctx context.Context, filter iface.FilterEmails, userIDs []int64) (map[int64]*iface.EmailsPage, error) {
+	return storage.FilterEmailsByUserIDs(ctx, s.sql, filter, userIDs)
+}
@@ -0,0 +1,100 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddEmail(t *testing.T) {
+	ctx := context.Background()
+	r := newStorage(t)
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	// succeed
+	emailID, err := r.AddEmail(ctx, tx, 3, "user@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, int(emailID))
+
+	// fails if duplicate
+	emailID, err = r.AddEmail(ctx, tx, 4, "user@example.com")
+	assert.Equal(t, iface.ErrAlreadyExists, err)
+	assert.Equal(t, 0, int(emailID))
+	assert.Nil(t, tx.Commit())
+}
+
+func TestDeleteEmail(t *testing.T) {
+	ctx := context.Background()
+	r := newStorage(t)
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	emailID, err := r.AddEmail(ctx, tx, 3, "user@example.com")
+	assert.Nil(t, err)
+
+	// succeed
+	assert.Nil(t, r.DeleteEmail(ctx, tx, emailID))
+
+	// fails if not found
+	assert.Equal(t, iface.ErrNotFound, r.DeleteEmail(ctx, tx, emailID))
+	assert.Nil(t, tx.Commit())
+}
+
+func TestDeleteEmailsByUserID(t *testing.T) {
+	ctx := context.Background()
+	r := newStorage(t)
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	_, err = r.AddEmail(ctx, tx, 3, "a@example.com")
+	assert.Nil(t, err)
+	_, err = r.AddEmail(ctx, tx, 3, "b@example.com")
+	assert.Nil(t, err)
+
+	// succeed
+	assert.Nil(t, r.DeleteEmailsByUserID(ctx, tx, 3))
+
+	// fails if not found
+	assert.Equal(t, iface.ErrNotFound, r.DeleteEmailsByUserID(ctx, tx, 3))
+	assert.Nil(t, tx.Commit())
+}
+
+func TestFilterEmails(t *testing.T) {
+	ctx := context.Background()
+	r := newStorage(t)
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	_, err = r.AddEmail(ctx, tx, 3, "a@example.com")
+	assert.Nil(t, err)
+	emailID, err := r.AddEmail(ctx, tx, 3, "b@example.com")
+	assert.Nil(t, err)
+	_, err = r.AddEmail(ctx, tx, 4, "c@example.com")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	// by user ID
+	{
+		emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{UserID: 3})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 2)
+		assert.Equal(t, "a@example.com", emails[0].Address)
+		assert.Equal(t, int64(3), emails[0].UserID)
+		assert.False(t, emails[0].Created.IsZero())
+	}
+
+	// by email ID
+	{
+		emails, _, err := r.FilterEmails(ctx, iface.FilterEmails{EmailID: emailID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 1)
+		assert.Equal(t, "b@example.com", emails[0].Address)
+	}
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage"
+	"github.com/rafaelsq/errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// now is the current time in UTC, to the millisecond SQLite keeps; timeLayout is how
+// it is written, so times compared with the stored ones must be formatted alike.
+const (
+	now        = "strftime('%Y-%m-%d %H:%M:%f', 'now')"
+	timeLayout = "2006-01-02 15:04:05.000"
+)
+
+type Storage struct {
+	sql *sql.DB
+}
+
+func (s *Storage) Tx() (iface.Tx, error) {
+	tx, err := s.sql.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func New(sql *sql.DB) iface.Storage {
+	return &Storage{
+		sql: sql,
+	}
+}
+
+// Open opens the SQLite database at dsn; the schema is managed by pkg/migration.
+func Open(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, errors.New("could not open database").SetParent(err)
+	}
+
+	// SQLite allows a single writer; sharing one connection also keeps
+	// ":memory:" databases alive across calls.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		db.Close()
+		return nil, errors.New("could not set pragma").SetParent(err)
+	}
+
+	return db, nil
+}
+
+func Insert(ctx context.Context, tx iface.Tx, query string, args ...interface{}) (int64, error) {
+	stx, err := storage.SQLTx(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := stx.ExecContext(ctx, query, args...)
+	if err != nil {
+		if sqliteError, ok := err.(*sqlite.Error); ok {
+			switch sqliteError.Code() {
+			case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+				return 0, iface.ErrAlreadyExists
+			}
+		}
+
+		return 0, errors.New("could not insert").SetArg("args", args).SetParent(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, errors.New("fail to retrieve last inserted ID").SetParent(err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,11 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, newStorage)
+}
@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rafaelsq/boiler/pkg/entity"
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage"
+)
+
+func (s *Storage) AddUser(ctx context.Context, tx iface.Tx, name string) (int64, error) {
+	return Insert(ctx, tx,
+		"INSERT INTO users (name, created, updated) VALUES (?, "+now+", "+now+")",
+		name,
+	)
+}
+
+func (s *Storage) UpdateUser(ctx context.Context, tx iface.Tx, userID, version int64, name string) error {
+	return storage.UpdateUser(ctx, tx, now, userID, version, name)
+}
+
+func (s *Storage) DeleteUser(ctx context.Context, tx iface.Tx, userID, version int64) error {
+	return storage.DeleteUser(ctx, tx, now, userID, version)
+}
+
+func (s *Storage) RestoreUser(ctx context.Context, tx iface.Tx, userID, version int64) error {
+	return storage.RestoreUser(ctx, tx, now, userID, version)
+}
+
+func (s *Storage) FilterUsersID(ctx context.Context, filter iface.FilterUsers) ([]int64, string, error) {
+	return storage.FilterUsersID(ctx, s.sql, timeArg, filter)
+}
+
+func (s *Storage) CountUsers(ctx context.Context, filter iface.FilterUsers) (int64, error) {
+	return storage.CountUsers(ctx, s.sql, timeArg, filter)
+}
+
+// timeArg formats t as the stored times are, which are compared as text.
+func timeArg(t time.Time) interface{} {
+	return t.UTC().Format(timeLayout)
+}
+
+func (s *Storage) FetchUsers(ctx context.Context, includeDeleted bool, IDs ...int64) ([]*entity.User, error) {
+	if len(IDs) == 0 {
+		return []*entity.User{}, nil
+	}
+
+	// SQLite has no FIELD(); rows are put back in the requested order below
+	query := fmt.Sprintf(
+		"SELECT id, name, created, updated, version, deleted_at FROM users WHERE id IN (%s)",
+		strings.Repeat("?,", len(IDs))[0:len(IDs)*2-1])
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+
+	args := make([]interface{}, 0, len(IDs))
+	for _, ID := range IDs {
+		args = append(args, ID)
+	}
+	rows, err := storage.Select(ctx, s.sql, storage.ScanUser, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	musers := make(map[int64]*entity.User, len(rows))
+	for _, row := range rows {
+		user := row.(*entity.User)
+		musers[user.ID] = user
+	}
+
+	users := make([]*entity.User, 0, len(rows))
+	for _, ID := range IDs {
+		if user, has := musers[ID]; has {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,132 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/migration"
+	"github.com/rafaelsq/boiler/pkg/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStorage(t *testing.T) iface.Storage {
+	db, err := sqlite.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migration.New(db, migration.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return sqlite.New(db)
+}
+
+func TestAddUser(t *testing.T) {
+	ctx := context.Background()
+	r := newStorage(t)
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	userID, err := r.AddUser(ctx, tx, "user")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, int(userID))
+	assert.Nil(t, tx.Commit())
+
+	users, err := r.FetchUsers(ctx, false, userID)
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "user", users[0].Name)
+	assert.False(t, users[0].Created.IsZero())
+	assert.False(t, users[0].Updated.IsZero())
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	r := newStorage(t)
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	userID, err := r.AddUser(ctx, tx, "user")
+	assert.Nil(t, err)
+
+	// fails if version is stale
+	assert.Equal(t, iface.ErrConflict, r.DeleteUser(ctx, tx, userID, 2))
+
+	// succeed
+	assert.Nil(t, r.DeleteUser(ctx, tx, userID, 1))
+
+	// fails if not found
+	assert.Equal(t, iface.ErrNotFound, r.DeleteUser(ctx, tx, userID, 1))
+	assert.Nil(t, tx.Commit())
+}
+
+func TestFilterUsersID(t *testing.T) {
+	ctx := context.Background()
+	r := newStorage(t)
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := r.AddUser(ctx, tx, name)
+		assert.Nil(t, err)
+	}
+
+	_, err = r.AddEmail(ctx, tx, 2, "b@example.com")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	// limit
+	{
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{1, 2}, IDs)
+	}
+
+	// by email
+	{
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: "b@example.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{2}, IDs)
+	}
+}
+
+func TestFetchUsers(t *testing.T) {
+	ctx := context.Background()
+	r := newStorage(t)
+
+	tx, err := r.Tx()
+	assert.Nil(t, err)
+
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := r.AddUser(ctx, tx, name)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tx.Commit())
+
+	// keeps the requested order and skips missing IDs
+	{
+		users, err := r.FetchUsers(ctx, false, 3, 9, 1)
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, "c", users[0].Name)
+		assert.Equal(t, "a", users[1].Name)
+	}
+
+	// succeed with no IDs
+	{
+		users, err := r.FetchUsers(ctx, false)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+	}
+}
@@ -3,6 +3,9 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/errors"
@@ -11,12 +14,20 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 )
 
+// ErrInvalidTx is returned when a transaction was not started by a SQL storage.
+var ErrInvalidTx = errors.New("invalid transaction")
+
 type Storage struct {
 	sql *sql.DB
 }
 
-func (s *Storage) Tx() (*sql.Tx, error) {
-	return s.sql.Begin()
+func (s *Storage) Tx() (iface.Tx, error) {
+	tx, err := s.sql.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
 }
 
 func New(sql *sql.DB) iface.Storage {
@@ -25,8 +36,23 @@ func New(sql *sql.DB) iface.Storage {
 	}
 }
 
-func Insert(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (int64, error) {
-	result, err := tx.ExecContext(ctx, query, args...)
+// SQLTx returns the *sql.Tx behind tx.
+func SQLTx(tx iface.Tx) (*sql.Tx, error) {
+	stx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, ErrInvalidTx
+	}
+
+	return stx, nil
+}
+
+func Insert(ctx context.Context, tx iface.Tx, query string, args ...interface{}) (int64, error) {
+	stx, err := SQLTx(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := stx.ExecContext(ctx, query, args...)
 	if err != nil {
 		if mysqlError, ok := err.(*mysql.MySQLError); ok {
 			if mysqlError.Number == 1062 {
@@ -45,8 +71,13 @@ func Insert(ctx context.Context, tx *sql.Tx, query string, args ...interface{})
 	return id, nil
 }
 
-func Delete(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) error {
-	result, err := tx.ExecContext(ctx, query, args...)
+func Delete(ctx context.Context, tx iface.Tx, query string, args ...interface{}) error {
+	stx, err := SQLTx(tx)
+	if err != nil {
+		return err
+	}
+
+	result, err := stx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return errors.New("could not remove").SetArg("args", args).SetParent(err)
 	}
@@ -63,6 +94,48 @@ func Delete(ctx context.Context, tx *sql.Tx, query string, args ...interface{})
 	return nil
 }
 
+func Update(ctx context.Context, tx iface.Tx, query string, args ...interface{}) error {
+	stx, err := SQLTx(tx)
+	if err != nil {
+		return err
+	}
+
+	result, err := stx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.New("could not update").SetArg("args", args).SetParent(err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("could not fetch rows affected").SetArg("args", args).SetParent(err)
+	}
+
+	if n == 0 {
+		return iface.ErrNotFound
+	}
+
+	return nil
+}
+
+// Exists reports whether query returns a row within tx.
+func Exists(ctx context.Context, tx iface.Tx, query string, args ...interface{}) (bool, error) {
+	stx, err := SQLTx(tx)
+	if err != nil {
+		return false, err
+	}
+
+	var found int
+	err = stx.QueryRowContext(ctx, query, args...).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.New("could not check existence").SetArg("args", args).SetParent(err)
+	}
+
+	return true, nil
+}
+
 func Select(ctx context.Context, sql *sql.DB, scan func(func(...interface{}) error) (interface{}, error), query string, args ...interface{}) ([]interface{}, error) {
 	rawRows, err := sql.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -87,6 +160,101 @@ func Select(ctx context.Context, sql *sql.DB, scan func(func(...interface{}) err
 	return rows, nil
 }
 
+// Count returns the single number query selects.
+func Count(ctx context.Context, sql *sql.DB, query string, args ...interface{}) (int64, error) {
+	var n int64
+	if err := sql.QueryRowContext(ctx, query, args...).Scan(&n); err != nil {
+		cerr := errors.New("could not count rows")
+		cerr.Caller = errors.Caller(1)
+		return 0, cerr.SetArg("args", args).SetParent(err)
+	}
+
+	return n, nil
+}
+
+// Keyset returns the ID comparison, the order and the bound ID of a page
+// resuming at cursor; backward pages start from the last row without one.
+func Keyset(cursor string, backward bool) (string, string, int64, error) {
+	ID, err := iface.DecodeCursor(cursor)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	if !backward {
+		return ">", "", ID, nil
+	}
+
+	if ID == 0 {
+		ID = math.MaxInt64
+	}
+
+	return "<", " DESC", ID, nil
+}
+
+// UserKeyset returns the condition, with its args, and the ORDER BY clause of the page
+// of users resuming at the filter's cursor; listed by another field than the ID, the
+// page resumes after the cursor's user in that order.
+func UserKeyset(filter iface.FilterUsers) (string, []interface{}, string, error) {
+	cmp, order, bound, err := Keyset(filter.Cursor, filter.Backward != filter.Desc)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	var column string
+	switch filter.OrderBy {
+	case iface.UserOrderName:
+		column = "name"
+	case iface.UserOrderCreated:
+		column = "created"
+	default:
+		return "u.id " + cmp + " ?", []interface{}{bound}, "u.id" + order, nil
+	}
+
+	orderBy := "u." + column + order + ", u.id" + order
+	if len(filter.Cursor) == 0 {
+		return "", nil, orderBy, nil
+	}
+
+	cond := "(u." + column + ", u.id) " + cmp + " (SELECT " + column + ", id FROM users WHERE id = ?)"
+	return cond, []interface{}{bound}, orderBy, nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern for ESCAPE '!', which both MySQL and SQLite accept.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// EscapeLike returns s as a literal within a LIKE pattern ending with ESCAPE '!'.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// Page trims rows fetched one over limit in keyset order, puts them back in the
+// listed order and returns the cursor of the following page, empty on the last one.
+func Page(rows []interface{}, limit uint, backward bool, ID func(row interface{}) int64) ([]interface{}, string) {
+	var next string
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		next = iface.EncodeCursor(ID(rows[len(rows)-1]))
+	}
+
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	return rows, next
+}
+
+// UTC returns *t in UTC, or nil; scans return times in UTC whatever location the driver gives them.
+func UTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
+}
+
 func scanInt(sc func(dest ...interface{}) error) (interface{}, error) {
 	var id int64
 
@@ -0,0 +1,847 @@
+// Package storagetest checks that an iface.Storage implementation honours the contract
+// the service relies on; backends and decorators run it from their own tests.
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns an empty storage; it is called once per subtest.
+type Factory func(t *testing.T) iface.Storage
+
+// Run runs the conformance suite against the storages returned by newStorage.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, iface.Storage)
+	}{
+		{"Tx", testTx},
+		{"AddUser", testAddUser},
+		{"UpdateUser", testUpdateUser},
+		{"DeleteUser", testDeleteUser},
+		{"RestoreUser", testRestoreUser},
+		{"FilterUsersID", testFilterUsersID},
+		{"FilterUsersIDOrder", testFilterUsersIDOrder},
+		{"FetchUsers", testFetchUsers},
+		{"AddEmail", testAddEmail},
+		{"DeleteEmail", testDeleteEmail},
+		{"DeleteEmailsByUserID", testDeleteEmailsByUserID},
+		{"FilterEmails", testFilterEmails},
+		{"FilterEmailsByUserIDs", testFilterEmailsByUserIDs},
+	}
+
+	for _, tc := range tests {
+		fn := tc.fn
+		t.Run(tc.name, func(t *testing.T) {
+			fn(t, newStorage(t))
+		})
+	}
+}
+
+// write runs fn in a transaction and commits it.
+func write(t *testing.T, s iface.Storage, fn func(tx iface.Tx)) {
+	tx, err := s.Tx()
+	require.Nil(t, err)
+
+	fn(tx)
+
+	require.Nil(t, tx.Commit())
+}
+
+func addUsers(t *testing.T, s iface.Storage, names ...string) []int64 {
+	IDs := make([]int64, 0, len(names))
+	write(t, s, func(tx iface.Tx) {
+		for _, name := range names {
+			ID, err := s.AddUser(context.Background(), tx, name)
+			require.Nil(t, err)
+			IDs = append(IDs, ID)
+		}
+	})
+
+	return IDs
+}
+
+func addEmails(t *testing.T, s iface.Storage, userID int64, addresses ...string) []int64 {
+	IDs := make([]int64, 0, len(addresses))
+	write(t, s, func(tx iface.Tx) {
+		for _, address := range addresses {
+			ID, err := s.AddEmail(context.Background(), tx, userID, address)
+			require.Nil(t, err)
+			IDs = append(IDs, ID)
+		}
+	})
+
+	return IDs
+}
+
+func testTx(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+
+	// rolled back writes are not visible
+	{
+		tx, err := s.Tx()
+		require.Nil(t, err)
+
+		userID, err := s.AddUser(ctx, tx, "rollback")
+		require.Nil(t, err)
+		_, err = s.AddEmail(ctx, tx, userID, "rollback@example.com")
+		require.Nil(t, err)
+
+		require.Nil(t, tx.Rollback())
+
+		users, err := s.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		IDs, _, err := s.FilterUsersID(ctx, iface.FilterUsers{})
+		assert.Nil(t, err)
+		assert.Len(t, IDs, 0)
+
+		emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 0)
+
+		// the address was released
+		addEmails(t, s, userID, "rollback@example.com")
+	}
+
+	// rolled back deletes keep the row
+	{
+		userID := addUsers(t, s, "kept")[0]
+
+		tx, err := s.Tx()
+		require.Nil(t, err)
+		require.Nil(t, s.DeleteUser(ctx, tx, userID, 1))
+		require.Nil(t, tx.Rollback())
+
+		users, err := s.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+	}
+}
+
+func testAddUser(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+
+	before := time.Now().Add(-time.Second)
+	IDs := addUsers(t, s, "a", "b")
+	assert.True(t, IDs[0] > 0)
+	assert.True(t, IDs[1] > IDs[0], "IDs should increase")
+
+	users, err := s.FetchUsers(ctx, false, IDs[0])
+	require.Nil(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, IDs[0], users[0].ID)
+	assert.Equal(t, "a", users[0].Name)
+
+	// times are UTC and close to now
+	assert.Equal(t, time.UTC, users[0].Created.Location())
+	assert.Equal(t, time.UTC, users[0].Updated.Location())
+	assert.WithinDuration(t, before, users[0].Created, time.Minute)
+	assert.False(t, users[0].Created.Before(before), "created %s before %s", users[0].Created, before)
+	assert.False(t, users[0].Updated.Before(users[0].Created))
+}
+
+func testUpdateUser(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	IDs := addUsers(t, s, "a")
+
+	// a read first, so decorators have something cached
+	users, err := s.FetchUsers(ctx, false, IDs...)
+	assert.Nil(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "a", users[0].Name)
+	assert.Equal(t, int64(1), users[0].Version)
+
+	created, updated := users[0].Created, users[0].Updated
+
+	write(t, s, func(tx iface.Tx) {
+		assert.Nil(t, s.UpdateUser(ctx, tx, IDs[0], 1, "b"))
+	})
+
+	users, err = s.FetchUsers(ctx, false, IDs...)
+	assert.Nil(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "b", users[0].Name)
+	assert.Equal(t, int64(2), users[0].Version)
+	assert.Equal(t, created, users[0].Created)
+	assert.False(t, users[0].Updated.Before(updated))
+
+	// succeed if name is unchanged
+	write(t, s, func(tx iface.Tx) {
+		assert.Nil(t, s.UpdateUser(ctx, tx, IDs[0], 2, "b"))
+	})
+
+	// rollback keeps the old name and version
+	{
+		tx, err := s.Tx()
+		require.Nil(t, err)
+		assert.Nil(t, s.UpdateUser(ctx, tx, IDs[0], 3, "c"))
+		assert.Nil(t, tx.Rollback())
+
+		users, err := s.FetchUsers(ctx, false, IDs...)
+		assert.Nil(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "b", users[0].Name)
+		assert.Equal(t, int64(3), users[0].Version)
+	}
+
+	// fails if version is stale
+	write(t, s, func(tx iface.Tx) {
+		assert.Equal(t, iface.ErrConflict, errors.Cause(s.UpdateUser(ctx, tx, IDs[0], 2, "c")))
+	})
+
+	// fails if not found
+	write(t, s, func(tx iface.Tx) {
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(s.UpdateUser(ctx, tx, IDs[0]+100, 1, "c")))
+	})
+}
+
+func testDeleteUser(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	IDs := addUsers(t, s, "a", "b")
+
+	// fails if version is stale
+	write(t, s, func(tx iface.Tx) {
+		assert.Nil(t, s.UpdateUser(ctx, tx, IDs[0], 1, "c"))
+		assert.Equal(t, iface.ErrConflict, errors.Cause(s.DeleteUser(ctx, tx, IDs[0], 1)))
+	})
+
+	write(t, s, func(tx iface.Tx) {
+		assert.Nil(t, s.DeleteUser(ctx, tx, IDs[0], 2))
+	})
+
+	// fails if not found
+	write(t, s, func(tx iface.Tx) {
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(s.DeleteUser(ctx, tx, IDs[0], 2)))
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(s.DeleteUser(ctx, tx, IDs[1]+100, 1)))
+	})
+
+	users, err := s.FetchUsers(ctx, false, IDs...)
+	assert.Nil(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, IDs[1], users[0].ID)
+}
+
+func testRestoreUser(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	userID := addUsers(t, s, "a")[0]
+	addEmails(t, s, userID, "a@example.com")
+
+	write(t, s, func(tx iface.Tx) {
+		require.Nil(t, s.DeleteUser(ctx, tx, userID, 1))
+	})
+
+	// a deleted user can not be changed and hides its emails
+	{
+		write(t, s, func(tx iface.Tx) {
+			assert.Equal(t, iface.ErrNotFound, errors.Cause(s.UpdateUser(ctx, tx, userID, 2, "b")))
+			assert.Equal(t, iface.ErrNotFound, errors.Cause(s.DeleteUser(ctx, tx, userID, 2)))
+		})
+
+		emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 0)
+
+		emails, _, err = s.FilterEmails(ctx, iface.FilterEmails{UserID: userID, IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 1)
+	}
+
+	// rollback keeps it deleted
+	{
+		tx, err := s.Tx()
+		require.Nil(t, err)
+		assert.Nil(t, s.RestoreUser(ctx, tx, userID, 2))
+		assert.Nil(t, tx.Rollback())
+
+		users, err := s.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+	}
+
+	// fails if version is stale
+	write(t, s, func(tx iface.Tx) {
+		assert.Equal(t, iface.ErrConflict, errors.Cause(s.RestoreUser(ctx, tx, userID, 1)))
+	})
+
+	write(t, s, func(tx iface.Tx) {
+		assert.Nil(t, s.RestoreUser(ctx, tx, userID, 2))
+	})
+
+	users, err := s.FetchUsers(ctx, false, userID)
+	assert.Nil(t, err)
+	require.Len(t, users, 1)
+	assert.Nil(t, users[0].DeletedAt)
+	assert.Equal(t, int64(3), users[0].Version)
+
+	emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: userID})
+	assert.Nil(t, err)
+	assert.Len(t, emails, 1)
+
+	// fails if not deleted or not found
+	write(t, s, func(tx iface.Tx) {
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(s.RestoreUser(ctx, tx, userID, 3)))
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(s.RestoreUser(ctx, tx, userID+100, 1)))
+	})
+}
+
+func testFilterUsersID(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	IDs := addUsers(t, s, "a", "b", "c")
+	addEmails(t, s, IDs[1], "b@example.com")
+
+	// limit
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs[:2], got)
+	}
+
+	// default limit
+	{
+		got, next, err := s.FilterUsersID(ctx, iface.FilterUsers{})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs, got)
+		assert.Empty(t, next)
+	}
+
+	// fails if cursor is invalid
+	{
+		_, _, err := s.FilterUsersID(ctx, iface.FilterUsers{Cursor: "opz"})
+		assert.Equal(t, iface.ErrInvalidCursor, err)
+	}
+
+	// by email
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{Email: "b@example.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs[1:2], got)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{Email: "none@example.com"})
+		assert.Nil(t, err)
+		assert.Len(t, got, 0)
+	}
+
+	// by email of a deleted user
+	{
+		write(t, s, func(tx iface.Tx) {
+			require.Nil(t, s.DeleteUser(ctx, tx, IDs[1], 1))
+		})
+
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{Email: "b@example.com"})
+		assert.Nil(t, err)
+		assert.Len(t, got, 0)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{Email: "b@example.com", IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs[1:2], got)
+	}
+
+	// deleted users are hidden unless included
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{IDs[0], IDs[2]}, got)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs, got)
+	}
+
+	// pages with the cursor; a page filled exactly is the last one
+	{
+		got, next, err := s.FilterUsersID(ctx, iface.FilterUsers{Limit: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs[:1], got)
+		require.NotEmpty(t, next)
+
+		// an insert after the first page is read does not shift the next ones
+		late := addUsers(t, s, "d")
+
+		got, next, err = s.FilterUsersID(ctx, iface.FilterUsers{Limit: 1, Cursor: next})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs[2:], got)
+		require.NotEmpty(t, next)
+
+		got, next, err = s.FilterUsersID(ctx, iface.FilterUsers{Limit: 1, Cursor: next})
+		assert.Nil(t, err)
+		assert.Equal(t, late, got)
+		assert.Empty(t, next)
+	}
+
+	// pages backward from the last user, in ID order
+	{
+		got, prev, err := s.FilterUsersID(ctx, iface.FilterUsers{Limit: 1, Backward: true})
+		assert.Nil(t, err)
+		require.Len(t, got, 1)
+		require.NotEmpty(t, prev)
+		last := got[0]
+
+		got, prev, err = s.FilterUsersID(ctx, iface.FilterUsers{Limit: 2, Cursor: prev, Backward: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{IDs[0], IDs[2]}, got)
+		assert.Empty(t, prev)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{Limit: 5, Cursor: iface.EncodeCursor(last), Backward: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{IDs[0], IDs[2]}, got)
+	}
+
+	// counts ignore the page
+	{
+		n, err := s.CountUsers(ctx, iface.FilterUsers{Limit: 1, Cursor: iface.EncodeCursor(IDs[0])})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), n)
+
+		n, err = s.CountUsers(ctx, iface.FilterUsers{IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), n)
+
+		n, err = s.CountUsers(ctx, iface.FilterUsers{Email: "b@example.com", IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), n)
+	}
+}
+
+func testFilterUsersIDOrder(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	IDs := addUsers(t, s, "bob", "alice", "carol", "alan")
+	bob, alice, carol, alan := IDs[0], IDs[1], IDs[2], IDs[3]
+	addEmails(t, s, alice, "alice@example.com", "alice@work.example.com", "al@Example.com")
+	addEmails(t, s, carol, "carol@other.org")
+
+	// by email domain, once per user
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{EmailDomain: "example.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{alice}, got)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{EmailDomain: "exampl_.com"})
+		assert.Nil(t, err)
+		assert.Len(t, got, 0)
+
+		n, err := s.CountUsers(ctx, iface.FilterUsers{EmailDomain: "example.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), n)
+	}
+
+	// by email and domain of the same address
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{Email: "carol@other.org", EmailDomain: "other.org"})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{carol}, got)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{Email: "carol@other.org", EmailDomain: "example.com"})
+		assert.Nil(t, err)
+		assert.Len(t, got, 0)
+	}
+
+	// by name prefix, ignoring case
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{NamePrefix: "Al"})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{alice, alan}, got)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{NamePrefix: "a%"})
+		assert.Nil(t, err)
+		assert.Len(t, got, 0)
+	}
+
+	// by creation time
+	{
+		now := time.Now()
+
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{CreatedFrom: now.Add(-48 * time.Hour)})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs, got)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{CreatedFrom: now.Add(48 * time.Hour)})
+		assert.Nil(t, err)
+		assert.Len(t, got, 0)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{CreatedBefore: now.Add(-48 * time.Hour)})
+		assert.Nil(t, err)
+		assert.Len(t, got, 0)
+
+		n, err := s.CountUsers(ctx, iface.FilterUsers{
+			CreatedFrom:   now.Add(-48 * time.Hour),
+			CreatedBefore: now.Add(48 * time.Hour),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), n)
+	}
+
+	// by name, in either direction
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{OrderBy: iface.UserOrderName})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{alan, alice, bob, carol}, got)
+
+		got, _, err = s.FilterUsersID(ctx, iface.FilterUsers{OrderBy: iface.UserOrderName, Desc: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{carol, bob, alice, alan}, got)
+	}
+
+	// by ID, descending
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{Desc: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{alan, carol, alice, bob}, got)
+	}
+
+	// by creation, ties in ID order
+	{
+		got, _, err := s.FilterUsersID(ctx, iface.FilterUsers{OrderBy: iface.UserOrderCreated})
+		assert.Nil(t, err)
+		assert.Equal(t, IDs, got)
+	}
+
+	// pages with the cursor in the given order
+	{
+		filter := iface.FilterUsers{OrderBy: iface.UserOrderName, Limit: 3}
+		got, next, err := s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{alan, alice, bob}, got)
+		require.NotEmpty(t, next)
+
+		filter.Cursor = next
+		got, next, err = s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{carol}, got)
+		assert.Empty(t, next)
+
+		filter = iface.FilterUsers{OrderBy: iface.UserOrderName, Desc: true, Limit: 2}
+		got, next, err = s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{carol, bob}, got)
+
+		filter.Cursor = next
+		got, _, err = s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{alice, alan}, got)
+	}
+
+	// pages backward in the given order
+	{
+		filter := iface.FilterUsers{OrderBy: iface.UserOrderName, Limit: 1, Backward: true}
+		got, prev, err := s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{carol}, got)
+
+		filter.Cursor, filter.Limit = prev, 2
+		got, prev, err = s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{alice, bob}, got)
+
+		filter.Cursor = prev
+		got, prev, err = s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{alan}, got)
+		assert.Empty(t, prev)
+	}
+
+	// pages through ties of the ordered field by ID
+	{
+		tied := addUsers(t, s, "bob")
+
+		filter := iface.FilterUsers{OrderBy: iface.UserOrderName, NamePrefix: "bob", Limit: 1}
+		got, next, err := s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{bob}, got)
+
+		filter.Cursor = next
+		got, next, err = s.FilterUsersID(ctx, filter)
+		assert.Nil(t, err)
+		assert.Equal(t, tied, got)
+		assert.Empty(t, next)
+	}
+}
+
+func testFetchUsers(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	IDs := addUsers(t, s, "a", "b", "c")
+
+	// keeps the requested order
+	{
+		users, err := s.FetchUsers(ctx, false, IDs[2], IDs[0], IDs[1])
+		assert.Nil(t, err)
+		require.Len(t, users, 3)
+		assert.Equal(t, "c", users[0].Name)
+		assert.Equal(t, "a", users[1].Name)
+		assert.Equal(t, "b", users[2].Name)
+	}
+
+	// skips missing IDs
+	{
+		users, err := s.FetchUsers(ctx, false, IDs[1], IDs[2]+100, IDs[0])
+		assert.Nil(t, err)
+		require.Len(t, users, 2)
+		assert.Equal(t, IDs[1], users[0].ID)
+		assert.Equal(t, IDs[0], users[1].ID)
+	}
+
+	// no IDs
+	{
+		users, err := s.FetchUsers(ctx, false)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+	}
+
+	// deleted users are hidden unless included
+	{
+		write(t, s, func(tx iface.Tx) {
+			require.Nil(t, s.DeleteUser(ctx, tx, IDs[1], 1))
+		})
+
+		users, err := s.FetchUsers(ctx, false, IDs...)
+		assert.Nil(t, err)
+		require.Len(t, users, 2)
+		assert.Equal(t, IDs[0], users[0].ID)
+		assert.Equal(t, IDs[2], users[1].ID)
+
+		users, err = s.FetchUsers(ctx, true, IDs...)
+		assert.Nil(t, err)
+		require.Len(t, users, 3)
+		assert.Nil(t, users[0].DeletedAt)
+		assert.NotNil(t, users[1].DeletedAt)
+	}
+}
+
+func testAddEmail(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	userID := addUsers(t, s, "a")[0]
+
+	IDs := addEmails(t, s, userID, "a@example.com", "b@example.com")
+	assert.True(t, IDs[0] > 0)
+	assert.True(t, IDs[1] > IDs[0], "IDs should increase")
+
+	// fails if the address exists
+	{
+		tx, err := s.Tx()
+		require.Nil(t, err)
+
+		ID, err := s.AddEmail(ctx, tx, userID+1, "a@example.com")
+		assert.Equal(t, iface.ErrAlreadyExists, errors.Cause(err))
+		assert.Equal(t, int64(0), ID)
+		require.Nil(t, tx.Rollback())
+	}
+
+	// fails if the address was added in the same transaction
+	{
+		tx, err := s.Tx()
+		require.Nil(t, err)
+
+		_, err = s.AddEmail(ctx, tx, userID, "c@example.com")
+		require.Nil(t, err)
+
+		_, err = s.AddEmail(ctx, tx, userID, "c@example.com")
+		assert.Equal(t, iface.ErrAlreadyExists, errors.Cause(err))
+		require.Nil(t, tx.Rollback())
+	}
+
+	emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: userID + 1})
+	assert.Nil(t, err)
+	assert.Len(t, emails, 0)
+}
+
+func testDeleteEmail(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	IDs := addEmails(t, s, 1, "a@example.com", "b@example.com")
+
+	write(t, s, func(tx iface.Tx) {
+		assert.Nil(t, s.DeleteEmail(ctx, tx, IDs[0]))
+	})
+
+	// fails if not found
+	write(t, s, func(tx iface.Tx) {
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(s.DeleteEmail(ctx, tx, IDs[0])))
+	})
+
+	emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: 1})
+	assert.Nil(t, err)
+	require.Len(t, emails, 1)
+	assert.Equal(t, IDs[1], emails[0].ID)
+
+	emails, _, err = s.FilterEmails(ctx, iface.FilterEmails{UserID: 1, IncludeDeleted: true})
+	assert.Nil(t, err)
+	require.Len(t, emails, 2)
+	assert.NotNil(t, emails[0].DeletedAt)
+	assert.Nil(t, emails[1].DeletedAt)
+
+	// the address can be reused
+	addEmails(t, s, 2, "a@example.com")
+}
+
+func testDeleteEmailsByUserID(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	addEmails(t, s, 1, "a@example.com", "b@example.com")
+	addEmails(t, s, 2, "c@example.com")
+
+	write(t, s, func(tx iface.Tx) {
+		assert.Nil(t, s.DeleteEmailsByUserID(ctx, tx, 1))
+	})
+
+	// fails if not found
+	write(t, s, func(tx iface.Tx) {
+		assert.Equal(t, iface.ErrNotFound, errors.Cause(s.DeleteEmailsByUserID(ctx, tx, 1)))
+	})
+
+	emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: 1})
+	assert.Nil(t, err)
+	assert.Len(t, emails, 0)
+
+	emails, _, err = s.FilterEmails(ctx, iface.FilterEmails{UserID: 2})
+	assert.Nil(t, err)
+	assert.Len(t, emails, 1)
+}
+
+func testFilterEmails(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	IDs := addEmails(t, s, 1, "a@example.com", "b@example.com", "c@example.com")
+	addEmails(t, s, 2, "d@example.com")
+
+	// by user ID
+	{
+		emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: 1})
+		assert.Nil(t, err)
+		require.Len(t, emails, 3)
+		for i, email := range emails {
+			assert.Equal(t, IDs[i], email.ID)
+			assert.Equal(t, int64(1), email.UserID)
+			assert.False(t, email.Created.IsZero())
+		}
+		assert.Equal(t, "a@example.com", emails[0].Address)
+	}
+
+	// limit
+	{
+		emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: 1, Limit: 2})
+		assert.Nil(t, err)
+		require.Len(t, emails, 2)
+		assert.Equal(t, IDs[0], emails[0].ID)
+		assert.Equal(t, IDs[1], emails[1].ID)
+	}
+
+	// pages with the cursor
+	{
+		emails, next, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: 1, Limit: 2})
+		assert.Nil(t, err)
+		require.Len(t, emails, 2)
+		require.NotEmpty(t, next)
+
+		emails, next, err = s.FilterEmails(ctx, iface.FilterEmails{UserID: 1, Limit: 2, Cursor: next})
+		assert.Nil(t, err)
+		require.Len(t, emails, 1)
+		assert.Equal(t, IDs[2], emails[0].ID)
+		assert.Empty(t, next)
+	}
+
+	// pages backward
+	{
+		emails, prev, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: 1, Limit: 2, Backward: true})
+		assert.Nil(t, err)
+		require.Len(t, emails, 2)
+		assert.Equal(t, IDs[1], emails[0].ID)
+		assert.Equal(t, IDs[2], emails[1].ID)
+		require.NotEmpty(t, prev)
+
+		emails, prev, err = s.FilterEmails(ctx, iface.FilterEmails{UserID: 1, Limit: 2, Cursor: prev, Backward: true})
+		assert.Nil(t, err)
+		require.Len(t, emails, 1)
+		assert.Equal(t, IDs[0], emails[0].ID)
+		assert.Empty(t, prev)
+	}
+
+	// counts ignore the page
+	{
+		n, err := s.CountEmails(ctx, iface.FilterEmails{UserID: 1, Limit: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), n)
+
+		n, err = s.CountEmails(ctx, iface.FilterEmails{UserID: 3})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), n)
+	}
+
+	// fails if cursor is invalid
+	{
+		_, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: 1, Cursor: "opz"})
+		assert.Equal(t, iface.ErrInvalidCursor, err)
+	}
+
+	// by email ID
+	{
+		emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{EmailID: IDs[1]})
+		assert.Nil(t, err)
+		require.Len(t, emails, 1)
+		assert.Equal(t, "b@example.com", emails[0].Address)
+	}
+
+	// no match
+	{
+		emails, _, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: 3})
+		assert.Nil(t, err)
+		assert.Len(t, emails, 0)
+	}
+}
+
+func testFilterEmailsByUserIDs(t *testing.T, s iface.Storage) {
+	ctx := context.Background()
+	IDs := addEmails(t, s, 1, "a@example.com", "b@example.com", "c@example.com")
+	otherIDs := addEmails(t, s, 2, "d@example.com")
+
+	// pages each user as FilterEmails would
+	{
+		pages, err := s.FilterEmailsByUserIDs(ctx, iface.FilterEmails{Limit: 2}, []int64{1, 2, 3})
+		assert.Nil(t, err)
+		require.Len(t, pages, 3)
+
+		for _, userID := range []int64{1, 2, 3} {
+			emails, next, err := s.FilterEmails(ctx, iface.FilterEmails{UserID: userID, Limit: 2})
+			assert.Nil(t, err)
+			require.NotNil(t, pages[userID])
+			assert.Equal(t, next, pages[userID].Next)
+			require.Len(t, pages[userID].Emails, len(emails))
+			for i, email := range emails {
+				assert.Equal(t, email.ID, pages[userID].Emails[i].ID)
+				assert.Equal(t, email.Address, pages[userID].Emails[i].Address)
+			}
+		}
+
+		assert.Equal(t, IDs[0], pages[1].Emails[0].ID)
+		assert.Equal(t, otherIDs[0], pages[2].Emails[0].ID)
+		assert.Len(t, pages[3].Emails, 0)
+	}
+
+	// pages with the cursor and backward
+	{
+		pages, err := s.FilterEmailsByUserIDs(ctx, iface.FilterEmails{Limit: 2, Cursor: iface.EncodeCursor(IDs[0])}, []int64{1, 2})
+		assert.Nil(t, err)
+		require.Len(t, pages[1].Emails, 2)
+		assert.Equal(t, IDs[1], pages[1].Emails[0].ID)
+		assert.Equal(t, IDs[2], pages[1].Emails[1].ID)
+		assert.Empty(t, pages[1].Next)
+		assert.Len(t, pages[2].Emails, 1)
+
+		pages, err = s.FilterEmailsByUserIDs(ctx, iface.FilterEmails{Limit: 2, Backward: true}, []int64{1})
+		assert.Nil(t, err)
+		require.Len(t, pages[1].Emails, 2)
+		assert.Equal(t, IDs[1], pages[1].Emails[0].ID)
+		assert.Equal(t, IDs[2], pages[1].Emails[1].ID)
+		assert.Equal(t, iface.EncodeCursor(IDs[1]), pages[1].Next)
+	}
+
+	// fails if cursor is invalid
+	{
+		_, err := s.FilterEmailsByUserIDs(ctx, iface.FilterEmails{Cursor: "opz"}, []int64{1})
+		assert.Equal(t, iface.ErrInvalidCursor, err)
+	}
+}
@@ -0,0 +1,36 @@
+package storage_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/rafaelsq/boiler/pkg/iface"
+	"github.com/rafaelsq/boiler/pkg/storage"
+	"github.com/rafaelsq/boiler/pkg/storage/storagetest"
+)
+
+// TestConformance runs against a disposable MariaDB; e.g.
+// BOILER_MYSQL_DSN="root:boiler@tcp(127.0.0.1:3307)/boiler?parseTime=true&loc=UTC"
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("BOILER_MYSQL_DSN")
+	if len(dsn) == 0 {
+		t.Skip("BOILER_MYSQL_DSN not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	storagetest.Run(t, func(t *testing.T) iface.Storage {
+		for _, table := range []string{"users", "emails"} {
+			if _, err := db.Exec("TRUNCATE TABLE " + table); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return storage.New(db)
+	})
+}
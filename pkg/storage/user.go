@@ -12,58 +12,224 @@ import (
 	"github.com/rafaelsq/errors"
 )
 
-func (s *Storage) AddUser(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
-	return Insert(ctx, tx, "INSERT INTO users (name, created, updated) VALUES (?, NOW(), NOW())", name)
+func (s *Storage) AddUser(ctx context.Context, tx iface.Tx, name string) (int64, error) {
+	return Insert(ctx, tx, "INSERT INTO users (name, created, updated) VALUES (?, "+now+", "+now+")", name)
 }
 
-func (s *Storage) DeleteUser(ctx context.Context, tx *sql.Tx, userID int64) error {
-	return Delete(ctx, tx, "DELETE FROM users WHERE id = ?", userID)
+// now is the current time in UTC, to the microsecond the columns keep.
+const now = "UTC_TIMESTAMP(6)"
+
+// timeArg passes times as they are; the driver writes them in UTC.
+func timeArg(t time.Time) interface{} {
+	return t
+}
+
+func (s *Storage) UpdateUser(ctx context.Context, tx iface.Tx, userID, version int64, name string) error {
+	return UpdateUser(ctx, tx, now, userID, version, name)
+}
+
+func (s *Storage) DeleteUser(ctx context.Context, tx iface.Tx, userID, version int64) error {
+	return DeleteUser(ctx, tx, now, userID, version)
+}
+
+func (s *Storage) RestoreUser(ctx context.Context, tx iface.Tx, userID, version int64) error {
+	return RestoreUser(ctx, tx, now, userID, version)
+}
+
+func (s *Storage) FilterUsersID(ctx context.Context, filter iface.FilterUsers) ([]int64, string, error) {
+	return FilterUsersID(ctx, s.sql, timeArg, filter)
+}
+
+func (s *Storage) CountUsers(ctx context.Context, filter iface.FilterUsers) (int64, error) {
+	return CountUsers(ctx, s.sql, timeArg, filter)
+}
+
+// UpdateUser renames the user at version within tx; now is the storage's expression
+// of the current time in UTC.
+func UpdateUser(ctx context.Context, tx iface.Tx, now string, userID, version int64, name string) error {
+	err := Update(ctx, tx,
+		"UPDATE users SET name = ?, version = version + 1, updated = "+now+" "+
+			"WHERE id = ? AND version = ? AND deleted_at IS NULL",
+		name, userID, version,
+	)
+	if err == iface.ErrNotFound {
+		return userVersionMismatch(ctx, tx, userID, false)
+	}
+
+	return err
+}
+
+// DeleteUser soft deletes the user at version within tx; now is the storage's
+// expression of the current time in UTC.
+func DeleteUser(ctx context.Context, tx iface.Tx, now string, userID, version int64) error {
+	err := Delete(ctx, tx,
+		"UPDATE users SET deleted_at = "+now+", version = version + 1, updated = "+now+" "+
+			"WHERE id = ? AND version = ? AND deleted_at IS NULL",
+		userID, version,
+	)
+	if err == iface.ErrNotFound {
+		return userVersionMismatch(ctx, tx, userID, false)
+	}
+
+	return err
+}
+
+// RestoreUser undoes the soft delete of the user at version within tx; now is the
+// storage's expression of the current time in UTC.
+func RestoreUser(ctx context.Context, tx iface.Tx, now string, userID, version int64) error {
+	err := Update(ctx, tx,
+		"UPDATE users SET deleted_at = NULL, version = version + 1, updated = "+now+" "+
+			"WHERE id = ? AND version = ? AND deleted_at IS NOT NULL",
+		userID, version,
+	)
+	if err == iface.ErrNotFound {
+		return userVersionMismatch(ctx, tx, userID, true)
+	}
+
+	return err
+}
+
+// userVersionMismatch tells a stale version from a missing user once a versioned write matched no row;
+// deleted is whether the write was on a deleted user.
+func userVersionMismatch(ctx context.Context, tx iface.Tx, userID int64, deleted bool) error {
+	cond := "deleted_at IS NULL"
+	if deleted {
+		cond = "deleted_at IS NOT NULL"
+	}
+
+	found, err := Exists(ctx, tx, "SELECT 1 FROM users WHERE id = ? AND "+cond, userID)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		return iface.ErrConflict
+	}
+
+	return iface.ErrNotFound
+}
+
+// usersFilter returns the conditions and their args of a users filter, without its page;
+// timeArg turns the times compared with the stored ones into args.
+func usersFilter(filter iface.FilterUsers, timeArg func(time.Time) interface{}) ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if len(filter.Email) != 0 || len(filter.EmailDomain) != 0 {
+		emailConds := []string{"e.user_id = u.id"}
+		if len(filter.Email) != 0 {
+			emailConds = append(emailConds, "e.address = ?")
+			args = append(args, filter.Email)
+		}
+		if len(filter.EmailDomain) != 0 {
+			emailConds = append(emailConds, "e.address LIKE ? ESCAPE '!'")
+			args = append(args, "%@"+EscapeLike(filter.EmailDomain))
+		}
+		if !filter.IncludeDeleted {
+			emailConds = append(emailConds, "e.deleted_at IS NULL")
+		}
+
+		conds = append(conds, "EXISTS (SELECT 1 FROM emails e WHERE "+strings.Join(emailConds, " AND ")+")")
+	}
+
+	if len(filter.NamePrefix) != 0 {
+		conds = append(conds, "u.name LIKE ? ESCAPE '!'")
+		args = append(args, EscapeLike(filter.NamePrefix)+"%")
+	}
+
+	if !filter.CreatedFrom.IsZero() {
+		conds = append(conds, "u.created >= ?")
+		args = append(args, timeArg(filter.CreatedFrom))
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		conds = append(conds, "u.created < ?")
+		args = append(args, timeArg(filter.CreatedBefore))
+	}
+
+	if !filter.IncludeDeleted {
+		conds = append(conds, "u.deleted_at IS NULL")
+	}
+
+	return conds, args
 }
 
-func (s *Storage) FilterUsersID(ctx context.Context, filter iface.FilterUsers) ([]int64, error) {
+// FilterUsersID returns a page of the IDs of the users matching filter and the cursor
+// of the next one; timeArg turns the times compared with the stored ones into args.
+func FilterUsersID(ctx context.Context, db *sql.DB, timeArg func(time.Time) interface{}, filter iface.FilterUsers) ([]int64, string, error) {
 	limit := iface.FilterUsersDefaultLimit
 	if filter.Limit != 0 {
 		limit = filter.Limit
 	}
 
-	var args []interface{}
-	var query string
+	keyset, keysetArgs, orderBy, err := UserKeyset(filter)
+	if err != nil {
+		return nil, "", err
+	}
 
-	if len(filter.Email) != 0 {
-		query = "SELECT u.id FROM users u INNER JOIN emails e ON(e.user_id = u.id) WHERE e.address = ?"
-		args = append(args, filter.Email)
-	} else {
-		query = "SELECT id FROM users LIMIT ?"
-		args = append(args, limit)
+	conds, args := usersFilter(filter, timeArg)
+	if len(keyset) != 0 {
+		conds = append(conds, keyset)
+		args = append(args, keysetArgs...)
 	}
 
-	rows, err := Select(ctx, s.sql, scanInt, query, args...)
+	// one row over the limit tells whether there is a following page
+	query := "SELECT u.id FROM users u"
+	if len(conds) != 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, limit+1)
+
+	rows, err := Select(ctx, db, scanInt, query+" ORDER BY "+orderBy+" LIMIT ?", args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
+	rows, next := Page(rows, limit, filter.Backward, func(row interface{}) int64 { return row.(int64) })
+
 	IDs := make([]int64, 0, len(rows))
 	for _, row := range rows {
-		if row != nil {
-			IDs = append(IDs, row.(int64))
-		}
+		IDs = append(IDs, row.(int64))
 	}
 
-	return IDs, nil
+	return IDs, next, nil
 }
 
-func (s *Storage) FetchUsers(ctx context.Context, IDs ...int64) ([]*entity.User, error) {
+// CountUsers returns how many users match filter, whatever its page; timeArg turns
+// the times compared with the stored ones into args.
+func CountUsers(ctx context.Context, db *sql.DB, timeArg func(time.Time) interface{}, filter iface.FilterUsers) (int64, error) {
+	conds, args := usersFilter(filter, timeArg)
+
+	query := "SELECT COUNT(*) FROM users u"
+	if len(conds) != 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	return Count(ctx, db, query, args...)
+}
+
+func (s *Storage) FetchUsers(ctx context.Context, includeDeleted bool, IDs ...int64) ([]*entity.User, error) {
+	if len(IDs) == 0 {
+		return []*entity.User{}, nil
+	}
+
+	where := " AND deleted_at IS NULL"
+	if includeDeleted {
+		where = ""
+	}
+
 	query := fmt.Sprintf(
-		"SELECT id, name, UNIX_TIMESTAMP(created), UNIX_TIMESTAMP(updated) "+
-			"FROM users WHERE id IN (%s) ORDER BY FIELD(id, %s)",
+		"SELECT id, name, created, updated, version, deleted_at "+
+			"FROM users WHERE id IN (%s)%s ORDER BY FIELD(id, %s)",
 		strings.Repeat("?,", len(IDs))[0:len(IDs)*2-1],
+		where,
 		strings.Repeat("?,", len(IDs))[0:len(IDs)*2-1])
 
 	args := make([]interface{}, 0, len(IDs)*2)
 	for _, ID := range append(IDs, IDs...) {
 		args = append(args, ID)
 	}
-	rows, err := Select(ctx, s.sql, scanUser, query, args...)
+	rows, err := Select(ctx, s.sql, ScanUser, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -76,13 +242,16 @@ func (s *Storage) FetchUsers(ctx context.Context, IDs ...int64) ([]*entity.User,
 	return users, nil
 }
 
-func scanUser(sc func(dest ...interface{}) error) (interface{}, error) {
+// ScanUser scans a row of the id, name, created, updated, version and deleted_at of a user.
+func ScanUser(sc func(dest ...interface{}) error) (interface{}, error) {
 	var id int64
 	var name string
 	var created time.Time
 	var updated time.Time
+	var version int64
+	var deletedAt *time.Time
 
-	err := sc(&id, &name, &created, &updated)
+	err := sc(&id, &name, &created, &updated, &version, &deletedAt)
 	if err != nil {
 		return nil, errors.New("could not scan user").SetParent(err)
 	}
@@ -90,7 +259,10 @@ func scanUser(sc func(dest ...interface{}) error) (interface{}, error) {
 	return &entity.User{
 		ID:      id,
 		Name:    name,
-		Created: created,
-		Updated: updated,
+		Created: created.UTC(),
+		Updated: updated.UTC(),
+		Version: version,
+
+		DeletedAt: UTC(deletedAt),
 	}, nil
 }
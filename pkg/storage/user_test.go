@@ -3,6 +3,7 @@ package storage_test
 import (
 	"context"
 	"fmt"
+	"math"
 	"regexp"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/rafaelsq/boiler/pkg/iface"
 	"github.com/rafaelsq/boiler/pkg/storage"
+	"github.com/rafaelsq/boiler/pkg/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,7 +29,7 @@ func TestAddUser(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("INSERT INTO users (name, created, updated) VALUES (?, NOW(), NOW())"),
+			regexp.QuoteMeta("INSERT INTO users (name, created, updated) VALUES (?, UTC_TIMESTAMP(6), UTC_TIMESTAMP(6))"),
 		).WithArgs(name).WillReturnResult(sqlmock.NewResult(3, 1))
 		mock.ExpectCommit()
 
@@ -49,7 +51,7 @@ func TestAddUser(t *testing.T) {
 		myErr := fmt.Errorf("err")
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("INSERT INTO users (name, created, updated) VALUES (?, NOW(), NOW())"),
+			regexp.QuoteMeta("INSERT INTO users (name, created, updated) VALUES (?, UTC_TIMESTAMP(6), UTC_TIMESTAMP(6))"),
 		).WithArgs(name).WillReturnError(myErr)
 		mock.ExpectCommit()
 
@@ -71,7 +73,7 @@ func TestAddUser(t *testing.T) {
 		myErr := fmt.Errorf("err")
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("INSERT INTO users (name, created, updated) VALUES (?, NOW(), NOW())"),
+			regexp.QuoteMeta("INSERT INTO users (name, created, updated) VALUES (?, UTC_TIMESTAMP(6), UTC_TIMESTAMP(6))"),
 		).WithArgs(name).WillReturnResult(sqlmock.NewResult(3, 1)).WillReturnResult(sqlmock.NewErrorResult(myErr))
 		mock.ExpectCommit()
 
@@ -85,10 +87,23 @@ func TestAddUser(t *testing.T) {
 		assert.Equal(t, 0, int(userID))
 		assert.Nil(t, tx.Commit())
 	}
+
+	// fails if tx is not a SQL transaction
+	{
+		tx, err := memory.New().Tx()
+		assert.Nil(t, err)
+
+		r := storage.New(mdb)
+
+		userID, err := r.AddUser(ctx, tx, "user")
+		assert.Equal(t, storage.ErrInvalidTx, err)
+		assert.Equal(t, 0, int(userID))
+	}
 }
 
-func TestDeleteUser(t *testing.T) {
+func TestUpdateUser(t *testing.T) {
 	ctx := context.Background()
+	version := int64(2)
 	mdb, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatal(err)
@@ -98,11 +113,13 @@ func TestDeleteUser(t *testing.T) {
 	// succeed
 	{
 		userID := int64(3)
+		name := "name"
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM users WHERE id = ?"),
-		).WithArgs(userID).WillReturnResult(sqlmock.NewResult(3, 1))
+			regexp.QuoteMeta("UPDATE users SET name = ?, version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(name, userID, version).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 
 		r := storage.New(mdb)
@@ -110,7 +127,7 @@ func TestDeleteUser(t *testing.T) {
 		tx, err := r.Tx()
 		assert.Nil(t, err)
 
-		err = r.DeleteUser(ctx, tx, userID)
+		err = r.UpdateUser(ctx, tx, userID, version, name)
 		assert.Nil(t, err)
 		assert.Nil(t, tx.Commit())
 		assert.Nil(t, mock.ExpectationsWereMet())
@@ -119,18 +136,153 @@ func TestDeleteUser(t *testing.T) {
 	// fails if exec fails
 	{
 		userID := int64(3)
+		name := "name"
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET name = ?, version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(name, userID, version).WillReturnError(fmt.Errorf("opz"))
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.UpdateUser(ctx, tx, userID, version, name)
+		assert.NotNil(t, err)
+		assert.Equal(t, err.Error(), "could not update; opz")
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+
+	// fails if no rows affected
+	{
+		userID := int64(3)
+		name := "name"
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET name = ?, version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(name, userID, version).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL"),
+		).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"1"}))
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.UpdateUser(ctx, tx, userID, version, name)
+		assert.Equal(t, err, iface.ErrNotFound)
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+
+	// fails if version is stale
+	{
+		userID := int64(3)
+		name := "name"
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET name = ?, version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(name, userID, version).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL"),
+		).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.UpdateUser(ctx, tx, userID, version, name)
+		assert.Equal(t, err, iface.ErrConflict)
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+
+	// fails if existence check fails
+	{
+		userID := int64(3)
+		name := "name"
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM users WHERE id = ?"),
+			regexp.QuoteMeta("UPDATE users SET name = ?, version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(name, userID, version).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL"),
 		).WithArgs(userID).WillReturnError(fmt.Errorf("opz"))
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.UpdateUser(ctx, tx, userID, version, name)
+		assert.Equal(t, err.Error(), "could not check existence; opz")
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	version := int64(2)
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mdb.Close()
+
+	// succeed
+	{
+		userID := int64(3)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET deleted_at = UTC_TIMESTAMP(6), version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(userID, version).WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.DeleteUser(ctx, tx, userID, version)
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+
+	// fails if exec fails
+	{
+		userID := int64(3)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET deleted_at = UTC_TIMESTAMP(6), version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(userID, version).WillReturnError(fmt.Errorf("opz"))
 
 		r := storage.New(mdb)
 
 		tx, err := r.Tx()
 		assert.Nil(t, err)
 
-		err = r.DeleteUser(ctx, tx, userID)
+		err = r.DeleteUser(ctx, tx, userID, version)
 		assert.NotNil(t, err)
 		assert.Equal(t, err.Error(), "could not remove; opz")
 	}
@@ -141,8 +293,9 @@ func TestDeleteUser(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM users WHERE id = ?"),
-		).WithArgs(userID).
+			regexp.QuoteMeta("UPDATE users SET deleted_at = UTC_TIMESTAMP(6), version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(userID, version).
 			WillReturnResult(sqlmock.NewResult(1, 1)).
 			WillReturnResult(sqlmock.NewErrorResult(fmt.Errorf("opz")))
 
@@ -153,7 +306,7 @@ func TestDeleteUser(t *testing.T) {
 		tx, err := r.Tx()
 		assert.Nil(t, err)
 
-		err = r.DeleteUser(ctx, tx, userID)
+		err = r.DeleteUser(ctx, tx, userID, version)
 		assert.NotNil(t, err)
 		assert.Equal(t, err.Error(), "could not fetch rows affected; opz")
 		assert.Nil(t, tx.Commit())
@@ -166,9 +319,13 @@ func TestDeleteUser(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(
-			regexp.QuoteMeta("DELETE FROM users WHERE id = ?"),
-		).WithArgs(userID).
+			regexp.QuoteMeta("UPDATE users SET deleted_at = UTC_TIMESTAMP(6), version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(userID, version).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL"),
+		).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"1"}))
 
 		mock.ExpectCommit()
 
@@ -177,12 +334,122 @@ func TestDeleteUser(t *testing.T) {
 		tx, err := r.Tx()
 		assert.Nil(t, err)
 
-		err = r.DeleteUser(ctx, tx, userID)
+		err = r.DeleteUser(ctx, tx, userID, version)
 		assert.NotNil(t, err)
 		assert.Equal(t, err, iface.ErrNotFound)
 		assert.Nil(t, tx.Commit())
 		assert.Nil(t, mock.ExpectationsWereMet())
 	}
+
+	// fails if version is stale
+	{
+		userID := int64(3)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET deleted_at = UTC_TIMESTAMP(6), version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NULL"),
+		).WithArgs(userID, version).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL"),
+		).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.DeleteUser(ctx, tx, userID, version)
+		assert.Equal(t, err, iface.ErrConflict)
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestRestoreUser(t *testing.T) {
+	ctx := context.Background()
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mdb.Close()
+
+	version := int64(2)
+
+	// succeed
+	{
+		userID := int64(3)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET deleted_at = NULL, version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NOT NULL"),
+		).WithArgs(userID, version).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.RestoreUser(ctx, tx, userID, version)
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+
+	// fails if not deleted
+	{
+		userID := int64(3)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET deleted_at = NULL, version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NOT NULL"),
+		).WithArgs(userID, version).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT 1 FROM users WHERE id = ? AND deleted_at IS NOT NULL"),
+		).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"1"}))
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.RestoreUser(ctx, tx, userID, version)
+		assert.Equal(t, err, iface.ErrNotFound)
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+
+	// fails if version is stale
+	{
+		userID := int64(3)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(
+			regexp.QuoteMeta("UPDATE users SET deleted_at = NULL, version = version + 1, updated = UTC_TIMESTAMP(6) "+
+				"WHERE id = ? AND version = ? AND deleted_at IS NOT NULL"),
+		).WithArgs(userID, version).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT 1 FROM users WHERE id = ? AND deleted_at IS NOT NULL"),
+		).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+		mock.ExpectCommit()
+
+		r := storage.New(mdb)
+
+		tx, err := r.Tx()
+		assert.Nil(t, err)
+
+		err = r.RestoreUser(ctx, tx, userID, version)
+		assert.Equal(t, err, iface.ErrConflict)
+		assert.Nil(t, tx.Commit())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
 }
 
 func TestFilterUsersID(t *testing.T) {
@@ -197,31 +464,121 @@ func TestFilterUsersID(t *testing.T) {
 	{
 		var limit uint = 3
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT id FROM users LIMIT ?"),
-		).WithArgs(limit).WillReturnRows(
+			regexp.QuoteMeta("SELECT u.id FROM users u WHERE u.deleted_at IS NULL AND u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(0, limit+1).WillReturnRows(
 			sqlmock.NewRows([]string{"id"}).
 				AddRow(3),
 		)
 
 		r := storage.New(mdb)
-		IDs, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit})
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit})
 		assert.Nil(t, err)
 		assert.Len(t, IDs, 1)
 		assert.Equal(t, 3, int(IDs[0]))
 	}
 
+	// succeed with next page
+	{
+		var limit uint = 2
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT u.id FROM users u WHERE u.deleted_at IS NULL AND u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(3, limit+1).WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).
+				AddRow(4).
+				AddRow(5).
+				AddRow(6),
+		)
+
+		r := storage.New(mdb)
+		IDs, next, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit, Cursor: iface.EncodeCursor(3)})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{4, 5}, IDs)
+		assert.Equal(t, iface.EncodeCursor(5), next)
+	}
+
+	// succeed backward
+	{
+		var limit uint = 2
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT u.id FROM users u WHERE u.deleted_at IS NULL AND u.id < ? ORDER BY u.id DESC LIMIT ?"),
+		).WithArgs(int64(math.MaxInt64), limit+1).WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).
+				AddRow(6).
+				AddRow(5).
+				AddRow(4),
+		)
+
+		r := storage.New(mdb)
+		IDs, prev, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit, Backward: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{5, 6}, IDs)
+		assert.Equal(t, iface.EncodeCursor(5), prev)
+	}
+
+	// succeed filtered and by name, descending, after the cursor's user
+	{
+		var limit uint = 2
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT u.id FROM users u WHERE "+
+				"EXISTS (SELECT 1 FROM emails e WHERE e.user_id = u.id AND e.address LIKE ? ESCAPE '!' AND e.deleted_at IS NULL)"+
+				" AND u.name LIKE ? ESCAPE '!' AND u.deleted_at IS NULL"+
+				" AND (u.name, u.id) < (SELECT name, id FROM users WHERE id = ?)"+
+				" ORDER BY u.name DESC, u.id DESC LIMIT ?"),
+		).WithArgs("%@ex!_ample.com", "jo!%%", 3, limit+1).WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).
+				AddRow(2),
+		)
+
+		r := storage.New(mdb)
+		IDs, next, err := r.FilterUsersID(ctx, iface.FilterUsers{
+			EmailDomain: "ex_ample.com",
+			NamePrefix:  "jo%",
+			OrderBy:     iface.UserOrderName,
+			Desc:        true,
+			Limit:       limit,
+			Cursor:      iface.EncodeCursor(3),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{2}, IDs)
+		assert.Empty(t, next)
+	}
+
+	// fails if cursor is invalid
+	{
+		r := storage.New(mdb)
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Cursor: "opz"})
+		assert.Equal(t, iface.ErrInvalidCursor, err)
+		assert.Nil(t, IDs)
+	}
+
+	// include deleted
+	{
+		var limit uint = 3
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT u.id FROM users u WHERE u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(0, limit+1).WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).
+				AddRow(3),
+		)
+
+		r := storage.New(mdb)
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit, IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Len(t, IDs, 1)
+	}
+
 	// fail scan
 	{
 		var limit uint = 2
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT id FROM users LIMIT ?"),
-		).WithArgs(limit).WillReturnRows(
+			regexp.QuoteMeta("SELECT u.id FROM users u WHERE u.deleted_at IS NULL AND u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(0, limit+1).WillReturnRows(
 			sqlmock.NewRows([]string{"id"}).
 				AddRow("err"),
 		)
 
 		r := storage.New(mdb)
-		IDs, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit})
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit})
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "invalid syntax")
 		assert.Len(t, IDs, 0)
@@ -233,16 +590,61 @@ func TestFilterUsersID(t *testing.T) {
 		myErr := fmt.Errorf("err")
 
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT id FROM users LIMIT ?"),
-		).WithArgs(limit).WillReturnError(myErr)
+			regexp.QuoteMeta("SELECT u.id FROM users u WHERE u.deleted_at IS NULL AND u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(0, limit+1).WillReturnError(myErr)
 
 		r := storage.New(mdb)
-		IDs, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit})
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Limit: limit})
 		assert.Equal(t, "could not fetch rows; err", err.Error())
 		assert.Len(t, IDs, 0)
 	}
 }
 
+func TestCountUsers(t *testing.T) {
+	ctx := context.Background()
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mdb.Close()
+
+	// succeed
+	{
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT COUNT(*) FROM users u WHERE u.deleted_at IS NULL"),
+		).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(3))
+
+		r := storage.New(mdb)
+		n, err := r.CountUsers(ctx, iface.FilterUsers{Limit: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), n)
+	}
+
+	// include deleted
+	{
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT COUNT(*) FROM users u"),
+		).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(4))
+
+		r := storage.New(mdb)
+		n, err := r.CountUsers(ctx, iface.FilterUsers{IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), n)
+	}
+
+	// fail
+	{
+		mock.ExpectQuery(
+			regexp.QuoteMeta("SELECT COUNT(*) FROM users u WHERE u.deleted_at IS NULL"),
+		).WillReturnError(fmt.Errorf("opz"))
+
+		r := storage.New(mdb)
+		n, err := r.CountUsers(ctx, iface.FilterUsers{})
+		assert.Equal(t, "could not count rows; opz", err.Error())
+		assert.Equal(t, int64(0), n)
+	}
+}
+
 func TestFetchUsers(t *testing.T) {
 	ctx := context.Background()
 	mdb, mock, err := sqlmock.New()
@@ -254,23 +656,25 @@ func TestFetchUsers(t *testing.T) {
 	// succeed
 	{
 		userID := int64(3)
+		created := time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.FixedZone("BRT", -3*60*60))
 		mock.ExpectQuery(
 			regexp.QuoteMeta(
-				"SELECT id, name, UNIX_TIMESTAMP(created), UNIX_TIMESTAMP(updated) "+
-					"FROM users WHERE id IN (?) ORDER BY FIELD(id, ?"),
+				"SELECT id, name, created, updated, version, deleted_at "+
+					"FROM users WHERE id IN (?) AND deleted_at IS NULL ORDER BY FIELD(id, ?"),
 		).WithArgs(userID, userID).WillReturnRows(
-			sqlmock.NewRows([]string{"id", "name", "created", "updated"}).
-				AddRow(userID, "user", time.Time{}, time.Time{}),
+			sqlmock.NewRows([]string{"id", "name", "created", "updated", "version", "deleted_at"}).
+				AddRow(userID, "user", created, created, 1, created),
 		)
 
 		r := storage.New(mdb)
-		users, err := r.FetchUsers(ctx, userID)
+		users, err := r.FetchUsers(ctx, false, userID)
 		assert.Nil(t, err)
 		assert.Len(t, users, 1)
 		assert.Equal(t, userID, users[0].ID)
 		assert.Equal(t, "user", users[0].Name)
-		assert.Equal(t, time.Time{}, users[0].Created)
-		assert.Equal(t, time.Time{}, users[0].Updated)
+		assert.Equal(t, created.UTC(), users[0].Created)
+		assert.Equal(t, created.UTC(), users[0].Updated)
+		assert.Equal(t, created.UTC(), *users[0].DeletedAt)
 	}
 
 	// succeed with no row
@@ -278,14 +682,22 @@ func TestFetchUsers(t *testing.T) {
 		userID := int64(3)
 		mock.ExpectQuery(
 			regexp.QuoteMeta(
-				"SELECT id, name, UNIX_TIMESTAMP(created), UNIX_TIMESTAMP(updated) "+
-					"FROM users WHERE id IN (?) ORDER BY FIELD(id, ?"),
+				"SELECT id, name, created, updated, version, deleted_at "+
+					"FROM users WHERE id IN (?) AND deleted_at IS NULL ORDER BY FIELD(id, ?"),
 		).WithArgs(userID, userID).WillReturnRows(
 			sqlmock.NewRows([]string{"id"}),
 		)
 
 		r := storage.New(mdb)
-		users, err := r.FetchUsers(ctx, userID)
+		users, err := r.FetchUsers(ctx, false, userID)
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+	}
+
+	// succeed with no IDs
+	{
+		r := storage.New(mdb)
+		users, err := r.FetchUsers(ctx, false)
 		assert.Nil(t, err)
 		assert.Len(t, users, 0)
 	}
@@ -295,15 +707,15 @@ func TestFetchUsers(t *testing.T) {
 		userID := int64(3)
 		mock.ExpectQuery(
 			regexp.QuoteMeta(
-				"SELECT id, name, UNIX_TIMESTAMP(created), UNIX_TIMESTAMP(updated) "+
-					"FROM users WHERE id IN (?) ORDER BY FIELD(id, ?"),
+				"SELECT id, name, created, updated, version, deleted_at "+
+					"FROM users WHERE id IN (?) AND deleted_at IS NULL ORDER BY FIELD(id, ?"),
 		).WithArgs(userID, userID).WillReturnRows(
-			sqlmock.NewRows([]string{"id", "name", "created", "updated"}).
-				AddRow("err", "user", 1, 2),
+			sqlmock.NewRows([]string{"id", "name", "created", "updated", "version", "deleted_at"}).
+				AddRow("err", "user", 1, 2, 1, nil),
 		)
 
 		r := storage.New(mdb)
-		users, err := r.FetchUsers(ctx, userID)
+		users, err := r.FetchUsers(ctx, false, userID)
 		assert.Contains(t, err.Error(), "invalid syntax")
 		assert.Nil(t, users)
 	}
@@ -314,12 +726,12 @@ func TestFetchUsers(t *testing.T) {
 		userID := int64(3)
 		mock.ExpectQuery(
 			regexp.QuoteMeta(
-				"SELECT id, name, UNIX_TIMESTAMP(created), UNIX_TIMESTAMP(updated) "+
-					"FROM users WHERE id IN (?) ORDER BY FIELD(id, ?"),
+				"SELECT id, name, created, updated, version, deleted_at "+
+					"FROM users WHERE id IN (?) AND deleted_at IS NULL ORDER BY FIELD(id, ?"),
 		).WithArgs(userID, userID).WillReturnError(myErr)
 
 		r := storage.New(mdb)
-		users, err := r.FetchUsers(ctx, userID)
+		users, err := r.FetchUsers(ctx, false, userID)
 		assert.Equal(t, err.Error(), "could not fetch rows; opz")
 		assert.Nil(t, users)
 	}
@@ -337,15 +749,16 @@ func TestFilterUsersByMail(t *testing.T) {
 	{
 		email := "example@example.com"
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT u.id FROM users u" +
-				" INNER JOIN emails e ON(e.user_id = u.id) WHERE e.address = ?"),
-		).WithArgs(email).WillReturnRows(
+			regexp.QuoteMeta("SELECT u.id FROM users u"+
+				" WHERE EXISTS (SELECT 1 FROM emails e WHERE e.user_id = u.id AND e.address = ? AND e.deleted_at IS NULL)"+
+				" AND u.deleted_at IS NULL AND u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(email, 0, iface.FilterUsersDefaultLimit+1).WillReturnRows(
 			sqlmock.NewRows([]string{"id"}).
 				AddRow(3),
 		)
 
 		r := storage.New(mdb)
-		IDs, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: email})
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: email})
 		assert.Nil(t, err)
 		assert.Equal(t, 3, int(IDs[0]))
 	}
@@ -354,14 +767,15 @@ func TestFilterUsersByMail(t *testing.T) {
 	{
 		email := "example@example.com"
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT u.id FROM users u" +
-				" INNER JOIN emails e ON(e.user_id = u.id) WHERE e.address = ?"),
-		).WithArgs(email).WillReturnRows(
+			regexp.QuoteMeta("SELECT u.id FROM users u"+
+				" WHERE EXISTS (SELECT 1 FROM emails e WHERE e.user_id = u.id AND e.address = ? AND e.deleted_at IS NULL)"+
+				" AND u.deleted_at IS NULL AND u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(email, 0, iface.FilterUsersDefaultLimit+1).WillReturnRows(
 			sqlmock.NewRows([]string{"id"}),
 		)
 
 		r := storage.New(mdb)
-		IDs, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: email})
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: email})
 		assert.Nil(t, err)
 		assert.Len(t, IDs, 0)
 	}
@@ -370,15 +784,16 @@ func TestFilterUsersByMail(t *testing.T) {
 	{
 		email := "example@example.com"
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT u.id FROM users u" +
-				" INNER JOIN emails e ON(e.user_id = u.id) WHERE e.address = ?"),
-		).WithArgs(email).WillReturnRows(
+			regexp.QuoteMeta("SELECT u.id FROM users u"+
+				" WHERE EXISTS (SELECT 1 FROM emails e WHERE e.user_id = u.id AND e.address = ? AND e.deleted_at IS NULL)"+
+				" AND u.deleted_at IS NULL AND u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(email, 0, iface.FilterUsersDefaultLimit+1).WillReturnRows(
 			sqlmock.NewRows([]string{"id"}).
 				AddRow("err"),
 		)
 
 		r := storage.New(mdb)
-		IDs, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: email})
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: email})
 		assert.Contains(t, err.Error(), "invalid syntax")
 		assert.Nil(t, IDs)
 	}
@@ -388,12 +803,13 @@ func TestFilterUsersByMail(t *testing.T) {
 		myErr := fmt.Errorf("opz")
 		email := "example@example.com"
 		mock.ExpectQuery(
-			regexp.QuoteMeta("SELECT u.id FROM users u" +
-				" INNER JOIN emails e ON(e.user_id = u.id) WHERE e.address = ?"),
-		).WithArgs(email).WillReturnError(myErr)
+			regexp.QuoteMeta("SELECT u.id FROM users u"+
+				" WHERE EXISTS (SELECT 1 FROM emails e WHERE e.user_id = u.id AND e.address = ? AND e.deleted_at IS NULL)"+
+				" AND u.deleted_at IS NULL AND u.id > ? ORDER BY u.id LIMIT ?"),
+		).WithArgs(email, 0, iface.FilterUsersDefaultLimit+1).WillReturnError(myErr)
 
 		r := storage.New(mdb)
-		IDs, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: email})
+		IDs, _, err := r.FilterUsersID(ctx, iface.FilterUsers{Email: email})
 		assert.Equal(t, err.Error(), "could not fetch rows; opz")
 		assert.Nil(t, IDs)
 	}